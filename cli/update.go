@@ -57,6 +57,25 @@ func updateConfig(ctx context.Context, configDir string) error {
 		return err
 	}
 
+	// Store the scripts of the configuration in Elasticsearch.
+	return storeScripts(ctx, client, configPath)
+}
+
+// storeScripts stores the scripts of the configuration file in Elasticsearch, with an id namespaced by the index of the script.
+// The scripts are executed by id at query time, so they must be stored again whenever they change.
+func storeScripts(ctx context.Context, client *elasticsearch.Client, configPath string) error {
+	configuration, err := connector.GetConfiguration(configPath, "")
+	if err != nil {
+		return err
+	}
+
+	for scriptName, script := range configuration.Scripts {
+		err := client.PutScript(ctx, script.GetStoredScriptId(scriptName), script.GetLang(), script.Source)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
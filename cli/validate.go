@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/hasura/ndc-elasticsearch/connector"
 	"github.com/hasura/ndc-elasticsearch/internal"
@@ -31,6 +32,24 @@ func validateConfig(configPath string) error {
 		return err
 	}
 
+	// Validate the scripts
+	err = validateScripts(configuration.Scripts, configuration.Indices)
+	if err != nil {
+		return err
+	}
+
+	// Validate the runtime fields
+	err = validateRuntimeFields(configuration)
+	if err != nil {
+		return err
+	}
+
+	// Validate the aggregation settings
+	err = validateAggregationSettings(configuration)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -79,13 +98,179 @@ func validateNativeQueries(nativeQueries map[string]types.NativeQuery) error {
 			return fmt.Errorf("missing 'return_type' value in %s", queryName)
 		}
 
-		if queryConfig.ReturnType.Kind != "defination" && queryConfig.ReturnType.Kind != "index" {
+		if queryConfig.ReturnType.Kind != "defination" && queryConfig.ReturnType.Kind != "index" && queryConfig.ReturnType.Kind != "aggregations" {
 			return fmt.Errorf("invalid 'kind' value '%s' in %s", queryConfig.ReturnType.Kind, queryName)
 		}
 
 		if queryConfig.ReturnType.Kind == "defination" && queryConfig.ReturnType.Mappings == nil {
 			return fmt.Errorf("missing 'mappings' value for kind 'defination' in %s", queryName)
 		}
+
+		if queryConfig.ReturnType.Kind == "aggregations" {
+			if len(queryConfig.ReturnType.Aggregations) == 0 {
+				return fmt.Errorf("missing 'aggregations' value for kind 'aggregations' in %s", queryName)
+			}
+			if err := validateNativeAggregations(queryConfig.ReturnType.Aggregations); err != nil {
+				return fmt.Errorf("invalid 'aggregations' value in %s: %w", queryName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateNativeAggregations validates the result schemas of the aggregations of a native query of kind `aggregations`.
+// It checks that each aggregation has a type, and that only bucket aggregations have sub-aggregations,
+// whose names don't clash with the fields of the buckets.
+func validateNativeAggregations(aggregations map[string]types.NativeAggregation) error {
+	for name, aggregation := range aggregations {
+		if aggregation.Type == "" {
+			return fmt.Errorf("missing 'type' value in aggregation %s", name)
+		}
+
+		if len(aggregation.Aggregations) == 0 {
+			continue
+		}
+
+		if !connector.IsNativeBucketAggregationType(aggregation.Type) {
+			return fmt.Errorf("aggregation %s of type '%s' can't have sub-aggregations", name, aggregation.Type)
+		}
+
+		for subName := range aggregation.Aggregations {
+			if connector.IsNativeBucketField(subName) {
+				return fmt.Errorf("sub-aggregation %s of aggregation %s has the name of a field of the buckets", subName, name)
+			}
+		}
+
+		if err := validateNativeAggregations(aggregation.Aggregations); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateScripts validates the scripts in the configuration file.
+// It checks that each script has a source, belongs to a known index,
+// has params of known scalar types, and does not shadow a collection argument.
+func validateScripts(scripts map[string]types.Script, indices map[string]interface{}) error {
+	for scriptName, script := range scripts {
+		if script.Source == "" {
+			return fmt.Errorf("missing 'source' value in script %s", scriptName)
+		}
+
+		if _, ok := indices[script.Index]; !ok {
+			return fmt.Errorf("invalid 'index' value '%s' in script %s", script.Index, scriptName)
+		}
+
+		if _, ok := internal.CollectionArgumentsMap[scriptName]; ok {
+			return fmt.Errorf("script %s has the same name as the collection argument '%s'", scriptName, scriptName)
+		}
+
+		for paramName, paramType := range script.Params {
+			if _, ok := internal.ScalarTypeMap[paramType]; !ok {
+				return fmt.Errorf("invalid type '%s' for param '%s' in script %s", paramType, paramName, scriptName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// runtimeFieldTypes are the types supported by Elasticsearch for runtime fields.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/runtime-mapping-fields.html
+var runtimeFieldTypes = map[string]bool{
+	"boolean":   true,
+	"date":      true,
+	"double":    true,
+	"geo_point": true,
+	"ip":        true,
+	"keyword":   true,
+	"long":      true,
+}
+
+// validateRuntimeFields validates the runtime fields in the configuration file.
+// It checks that each runtime field belongs to a known index, has a script and a supported type,
+// and does not shadow a field of the index mappings.
+func validateRuntimeFields(configuration *types.Configuration) error {
+	for indexName, runtimeFields := range configuration.RuntimeFields {
+		if _, ok := configuration.Indices[indexName]; !ok {
+			return fmt.Errorf("invalid index '%s' in runtime_fields", indexName)
+		}
+
+		for fieldName, runtimeField := range runtimeFields {
+			if runtimeField.Script == "" {
+				return fmt.Errorf("missing 'script' value in runtime field %s of index %s", fieldName, indexName)
+			}
+
+			if !runtimeFieldTypes[runtimeField.Type] {
+				return fmt.Errorf("invalid type '%s' for runtime field %s of index %s", runtimeField.Type, fieldName, indexName)
+			}
+
+			index, err := configuration.GetIndex(indexName)
+			if err != nil {
+				return err
+			}
+			properties, _ := index["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
+			if _, ok := properties[fieldName]; ok {
+				return fmt.Errorf("runtime field %s has the same name as a field of index %s", fieldName, indexName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAggregationSettings validates the aggregation settings in the configuration file.
+// It checks that the percents of the `percentiles` aggregate function are between 0 and 100,
+// that the sort fields of `top_metrics` and the weight fields of `weighted_avg` are fields of known indices,
+// and that the precision thresholds of the distinct counts are at most 40000 and their exact fields are fields of known indices out of nested fields.
+func validateAggregationSettings(configuration *types.Configuration) error {
+	settings := configuration.Aggregations
+	if settings == nil {
+		return nil
+	}
+
+	for _, percent := range settings.Percents {
+		if percent < 0 || percent > 100 {
+			return fmt.Errorf("invalid percent %v in aggregations, expected a value between 0 and 100", percent)
+		}
+	}
+
+	for indexName, sort := range settings.TopMetricsSort {
+		if _, err := configuration.GetFieldMap(indexName, sort.Field); err != nil {
+			return fmt.Errorf("invalid top_metrics_sort of index %s in aggregations: %w", indexName, err)
+		}
+		if order := sort.GetOrder(); order != "asc" && order != "desc" {
+			return fmt.Errorf("invalid order '%s' in top_metrics_sort of index %s, expected asc or desc", order, indexName)
+		}
+	}
+
+	for indexName, weightField := range settings.WeightFields {
+		if _, err := configuration.GetFieldMap(indexName, weightField); err != nil {
+			return fmt.Errorf("invalid weight field of index %s in aggregations: %w", indexName, err)
+		}
+	}
+
+	for indexName, distinctCount := range settings.DistinctCounts {
+		if _, err := configuration.GetIndex(indexName); err != nil {
+			return fmt.Errorf("invalid distinct_counts of index %s in aggregations: %w", indexName, err)
+		}
+		if distinctCount.PrecisionThreshold < 0 || distinctCount.PrecisionThreshold > 40000 {
+			return fmt.Errorf("invalid precision_threshold %d of index %s in aggregations, expected a value between 0 and 40000", distinctCount.PrecisionThreshold, indexName)
+		}
+		for _, field := range distinctCount.ExactFields {
+			if _, err := configuration.GetFieldMap(indexName, field); err != nil {
+				return fmt.Errorf("invalid exact field of index %s in aggregations: %w", indexName, err)
+			}
+			// the field and the objects it belongs to must not be nested
+			splitField := strings.Split(field, ".")
+			for i := range splitField {
+				if isNested, _ := configuration.IsFieldNested(indexName, strings.Join(splitField[:i+1], ".")); isNested {
+					return fmt.Errorf("invalid exact field %s of index %s in aggregations, the distinct values of nested fields can't be counted exactly", field, indexName)
+				}
+			}
+		}
 	}
 
 	return nil
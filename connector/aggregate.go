@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/hasura/ndc-elasticsearch/internal"
 	"github.com/hasura/ndc-elasticsearch/types"
@@ -9,11 +10,30 @@ import (
 )
 
 // prepareAggregateQuery prepares the aggregation query based on the aggregates in the query request.
-func prepareAggregateQuery(ctx context.Context, aggregates schema.QueryAggregates, state *types.State, collection string) (map[string]interface{}, error) {
+// An aggregate with a predicate is wrapped in a filter aggregation, which only computes it on the documents matching the predicate.
+func prepareAggregateQuery(ctx context.Context, aggregates schema.QueryAggregates, predicates map[string]schema.Expression, state *types.State, collection string) (map[string]interface{}, error) {
 	var path string
 	aggregations := make(map[string]interface{})
 	postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
 	postProcessor.ColumnAggregate = make(map[string]bool)
+	postProcessor.AggregateFunctions = make(map[string]string)
+
+	filters := make(map[string]map[string]interface{}, len(predicates))
+	for aggregationName, predicate := range predicates {
+		if _, ok := aggregates[aggregationName]; !ok {
+			return nil, schema.UnprocessableContentError("aggregate_predicates has a predicate for an unknown aggregate", map[string]any{
+				"value": aggregationName,
+			})
+		}
+		filter, err := prepareFilterQuery(predicate, state, collection)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAggregationFilterVariables(filter, "aggregate_predicates", map[string]any{"value": aggregationName}); err != nil {
+			return nil, err
+		}
+		filters[aggregationName] = filter
+	}
 
 	for aggregationName, aggregation := range aggregates {
 		aggregationType, err := aggregation.Type()
@@ -22,6 +42,14 @@ func prepareAggregateQuery(ctx context.Context, aggregates schema.QueryAggregate
 		}
 
 		if aggregationType == schema.AggregateTypeStarCount {
+			if filter, ok := filters[aggregationName]; ok {
+				// the documents matching the predicate are counted by the doc_count of the filter aggregation
+				postProcessor.ColumnAggregate[aggregationName] = false
+				aggregations[aggregationName] = map[string]interface{}{
+					"filter": filter,
+				}
+				continue
+			}
 			postProcessor.StarAggregates = aggregationName
 			continue
 		}
@@ -45,6 +73,15 @@ func prepareAggregateQuery(ctx context.Context, aggregates schema.QueryAggregate
 		if err != nil {
 			return nil, err
 		}
+		if filter, ok := filters[aggregationName]; ok {
+			if postProcessor.ExactDistinctCounts[aggregationName] {
+				// the composite aggregation of an exact distinct count can't be a sub-aggregation
+				return nil, schema.UnprocessableContentError("exact distinct counts are not supported in aggregate_predicates", map[string]any{
+					"value": aggregationName,
+				})
+			}
+			aggregation = prepareFilteredAggregate(ctx, aggregationName, aggregation, filter)
+		}
 		aggregations[aggregationName] = aggregation
 		path = ""
 	}
@@ -87,10 +124,9 @@ func prepareAggregateColumnCount(ctx context.Context, state *types.State, collec
 		if err != nil {
 			return nil, err
 		}
-		aggregation = map[string]interface{}{
-			"cardinality": map[string]interface{}{
-				"field": bestFieldOrSubField,
-			},
+		aggregation, err = prepareDistinctCount(ctx, state, collection, field, bestFieldOrSubField, path, aggName)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// Otherwise, count all occurrences
@@ -127,10 +163,28 @@ func prepareAggregateSingleColumn(ctx context.Context, state *types.State, funct
 		return nil, err
 	}
 	// Prepare the aggregation query
+	options := map[string]interface{}{
+		"field": bestFieldOrSubField,
+	}
+	settings := state.Configuration.Aggregations
+	switch function {
+	case "percentiles", "percentile_ranks":
+		err = preparePercentilesOptions(options, function, settings)
+	case "top_metrics":
+		options, err = prepareTopMetricsOptions(bestFieldOrSubField, path, collection, settings)
+	case "weighted_avg":
+		options, err = prepareWeightedAvgOptions(bestFieldOrSubField, path, collection, settings)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if function == "percentiles" || function == "percentile_ranks" || function == "top_metrics" || internal.GeoPointAggregations[function] {
+		// the results of these aggregations are converted when they are extracted (see extractAggregates)
+		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+		postProcessor.AggregateFunctions[aggName] = function
+	}
 	aggregation := map[string]interface{}{
-		function: map[string]interface{}{
-			"field": bestFieldOrSubField,
-		},
+		function: options,
 	}
 
 	// If the field is nested, generate a nested query
@@ -173,3 +227,68 @@ func prepareNestedAggregate(ctx context.Context, aggName string, aggregation map
 
 	return aggregation
 }
+
+// prepareFilteredAggregate wraps an aggregation in a filter aggregation, to compute it on the documents matching the filter only.
+// Like the aggregations on nested fields, the aggregation is a sub-aggregation with the same name, unwrapped by extractAggregates.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-filter-aggregation.html
+func prepareFilteredAggregate(ctx context.Context, aggName string, aggregation map[string]interface{}, filter map[string]interface{}) map[string]interface{} {
+	postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+	postProcessor.ColumnAggregate[aggName] = true
+
+	return map[string]interface{}{
+		"filter": filter,
+		"aggs": map[string]interface{}{
+			aggName: aggregation,
+		},
+	}
+}
+
+// getAggregatePredicates returns the predicates of the aggregates, by name, from the `aggregate_predicates` collection argument.
+// The predicates have the format of the predicates of NDC queries, since NDC aggregates have no predicate.
+func getAggregatePredicates(arguments map[string]schema.Argument) (map[string]schema.Expression, error) {
+	arg, ok := arguments["aggregate_predicates"]
+	if !ok {
+		return nil, nil
+	}
+	value, err := evalArgument(&arg)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := value.(types.Variable); ok {
+		return nil, schema.UnprocessableContentError("variables are not supported in aggregate_predicates", nil)
+	}
+	values, ok := value.(map[string]interface{})
+	if !ok {
+		if value == nil {
+			return nil, nil
+		}
+		return nil, schema.UnprocessableContentError("invalid aggregate_predicates argument, expected an object of predicates by aggregate name", map[string]any{
+			"value": value,
+		})
+	}
+
+	predicates := make(map[string]schema.Expression, len(values))
+	for aggregationName, value := range values {
+		predicate, err := decodePredicate(aggregationName, value)
+		if err != nil {
+			return nil, err
+		}
+		predicates[aggregationName] = predicate
+	}
+	return predicates, nil
+}
+
+// decodePredicate decodes a predicate given as a JSON argument (e.g. the predicate of an aggregate), which has the format of the predicates of NDC queries.
+func decodePredicate(name string, value interface{}) (schema.Expression, error) {
+	predicateJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var predicate schema.Expression
+	if err := json.Unmarshal(predicateJSON, &predicate); err != nil {
+		return nil, schema.UnprocessableContentError("invalid predicate: "+err.Error(), map[string]any{
+			"value": name,
+		})
+	}
+	return predicate, nil
+}
@@ -0,0 +1,138 @@
+package connector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hasura/ndc-elasticsearch/internal"
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// collapseInnerHitsName is the name of the inner hits which hold the members of a collapsed group.
+const collapseInnerHitsName = "_collapsed"
+
+// prepareCollapse prepares the collapse option of the search from the `collapse` collection argument.
+// The members of each group are returned as inner hits when `inner_hits` is set, with the columns selected in the `_collapsed` column.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/collapse-search-results.html
+func prepareCollapse(ctx context.Context, request *schema.QueryRequest, state *types.State, index string) (map[string]interface{}, error) {
+	arg, ok := request.Arguments["collapse"]
+	if !ok {
+		return nil, nil
+	}
+	options, ok := removeNullOptions(arg.Value).(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	if len(request.Variables) != 0 {
+		return nil, schema.UnprocessableContentError("collapse is not supported in queries with variables", nil)
+	}
+
+	field, err := getCollapseField(options["field"], state, index)
+	if err != nil {
+		return nil, err
+	}
+	options["field"] = field
+
+	if innerHits, ok := options["inner_hits"].(map[string]interface{}); ok {
+		innerHits["name"] = collapseInnerHitsName
+
+		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+		if postProcessor.Collapsed == nil {
+			// the members are not selected, only their number is relevant
+			innerHits["_source"] = false
+		} else {
+			selection, err := prepareSelection(ctx, postProcessor.CollapsedFields, postProcessor.Collapsed, state, index)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range selection {
+				innerHits[key] = value
+			}
+		}
+	}
+
+	// search_after can only be used with collapse when the results are sorted on the collapse field
+	if _, ok := request.Arguments["search_after"]; ok && !isSortedOnField(request.Query.OrderBy, field) {
+		return nil, schema.UnprocessableContentError("search_after with collapse requires the results to be sorted on the collapse field only", map[string]any{
+			"value": field,
+		})
+	}
+
+	return options, nil
+}
+
+// getCollapseField validates the field to collapse on, which must be a sortable keyword or numeric field out of a nested field.
+// The keyword subfield of a text field is used to collapse on the text field.
+func getCollapseField(value interface{}, state *types.State, index string) (string, error) {
+	field, ok := value.(string)
+	if !ok || field == "" {
+		return "", schema.UnprocessableContentError("missing 'field' value in collapse", nil)
+	}
+
+	splitField := strings.Split(field, ".")
+	_, nestedPath := joinFieldPath(state, splitField[1:], splitField[0], index)
+	if nestedPath != "" || internal.ValidateSortOperation(state.SupportedSortFields, index, field) == "" {
+		return "", schema.UnprocessableContentError("collapsing not supported on this field", map[string]any{
+			"value": field,
+		})
+	}
+
+	fieldType, subFieldMap, _, err := state.Configuration.GetFieldProperties(index, field)
+	if err != nil {
+		return "", schema.UnprocessableContentError("unable to get field types", map[string]any{
+			"value": field,
+		})
+	}
+	if fieldType == "keyword" || internal.NumericFamilyOfTypes[fieldType] {
+		return field, nil
+	}
+	if keywordField, ok := subFieldMap["keyword"]; ok {
+		return field + "." + keywordField, nil
+	}
+
+	return "", schema.UnprocessableContentError("collapsing is only supported on keyword and numeric fields", map[string]any{
+		"value": field,
+	})
+}
+
+// isSortedOnField checks if the results are sorted on the given field only.
+func isSortedOnField(orderBy *schema.OrderBy, field string) bool {
+	if orderBy == nil || len(orderBy.Elements) != 1 {
+		return false
+	}
+	column, ok := orderBy.Elements[0].Target.Interface().(*schema.OrderByColumn)
+	if !ok {
+		return false
+	}
+	columnPath := strings.Join(append([]string{column.Name}, column.FieldPath...), ".")
+	return columnPath == field || strings.HasPrefix(field, columnPath+".")
+}
+
+// extractCollapsedHits returns the members of the collapsed group of a hit, from its inner hits.
+func extractCollapsedHits(hit map[string]interface{}, postProcessor *types.PostProcessor) []interface{} {
+	members := make([]interface{}, 0)
+	innerHits, ok := hit["inner_hits"].(map[string]interface{})
+	if !ok {
+		return members
+	}
+	collapsed, ok := innerHits[collapseInnerHitsName].(map[string]interface{})
+	if !ok {
+		return members
+	}
+	collapsedHits, ok := collapsed["hits"].(map[string]interface{})
+	if !ok {
+		return members
+	}
+	memberHits, ok := collapsedHits["hits"].([]interface{})
+	if !ok {
+		return members
+	}
+	for _, member := range memberHits {
+		if member, ok := member.(map[string]interface{}); ok {
+			members = append(members, extractHit(member, postProcessor))
+		}
+	}
+	return members
+}
@@ -64,9 +64,10 @@ func (c *Connector) GetCapabilities(configuration *types.Configuration) schema.C
 		Version: "0.1.6",
 		Capabilities: schema.Capabilities{
 			Query: schema.QueryCapabilities{
-				Variables:  schema.LeafCapability{},
+				Variables: schema.LeafCapability{},
+				// NDC 0.1.6 has no grouping capability: grouping is exposed with the `group_by` collection argument
 				Aggregates: schema.LeafCapability{},
-				Explain:  schema.LeafCapability{},
+				Explain:    schema.LeafCapability{},
 				NestedFields: schema.NestedFieldCapabilities{
 					OrderBy:    schema.LeafCapability{},
 					FilterBy:   schema.LeafCapability{},
@@ -0,0 +1,217 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const (
+	// defaultPrecisionThreshold is the default precision threshold of the `cardinality` aggregation.
+	defaultPrecisionThreshold = 3000
+	// maxPrecisionThreshold is the maximum precision threshold of the `cardinality` aggregation.
+	maxPrecisionThreshold = 40000
+	// exactDistinctCountPageSize is the number of distinct values counted by each page of the composite aggregation of an exact distinct count.
+	exactDistinctCountPageSize = 10000
+	// exactDistinctCountLimit is the maximum number of distinct values counted exactly, above which an approximate count is required.
+	exactDistinctCountLimit = 100000
+)
+
+// distinctCountOptions are the options of the distinct counts of a request, from the `distinct_count` collection argument,
+// which override the distinct count settings of the index.
+type distinctCountOptions struct {
+	PrecisionThreshold int
+	Exact              *bool
+}
+
+// getDistinctCountOptions returns the options of the distinct counts of a request, from the `distinct_count` collection argument.
+func getDistinctCountOptions(arguments map[string]schema.Argument) (distinctCountOptions, error) {
+	var options distinctCountOptions
+	arg, ok := arguments["distinct_count"]
+	if !ok {
+		return options, nil
+	}
+	value, err := evalArgument(&arg)
+	if err != nil {
+		return options, err
+	}
+	if _, ok := value.(types.Variable); ok {
+		return options, schema.UnprocessableContentError("variables are not supported in distinct_count", nil)
+	}
+	values, _ := removeNullOptions(value).(map[string]interface{})
+
+	if threshold, ok := values["precision_threshold"]; ok {
+		precisionThreshold, ok := threshold.(float64)
+		if !ok || precisionThreshold != float64(int(precisionThreshold)) || precisionThreshold < 0 || precisionThreshold > maxPrecisionThreshold {
+			return options, schema.UnprocessableContentError(fmt.Sprintf("invalid precision_threshold in distinct_count, expected an integer between 0 and %d", maxPrecisionThreshold), map[string]any{
+				"value": threshold,
+			})
+		}
+		options.PrecisionThreshold = int(precisionThreshold)
+	}
+	if exact, ok := values["exact"]; ok {
+		isExact, ok := exact.(bool)
+		if !ok {
+			return options, schema.UnprocessableContentError("invalid exact in distinct_count, expected a boolean", map[string]any{
+				"value": exact,
+			})
+		}
+		options.Exact = &isExact
+	}
+	return options, nil
+}
+
+// getDistinctCountContext returns the options of the distinct counts of the request from the context.
+func getDistinctCountContext(ctx context.Context) distinctCountOptions {
+	options, _ := ctx.Value("distinctCount").(distinctCountOptions)
+	return options
+}
+
+// prepareDistinctCount prepares the aggregation which counts the distinct values of a field.
+// The values are counted approximately by a `cardinality` aggregation, with the precision threshold of the request or of the index,
+// or exactly by a `composite` aggregation on the values of the field, whose pages are counted by countExactDistinctValues.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-cardinality-aggregation.html
+func prepareDistinctCount(ctx context.Context, state *types.State, collection string, field string, bestFieldOrSubField string, path string, aggName string) (map[string]interface{}, error) {
+	settings := state.Configuration.Aggregations.GetDistinctCountSettings(collection)
+	options := getDistinctCountContext(ctx)
+
+	isExact := settings.IsExact(field)
+	if options.Exact != nil {
+		isExact = *options.Exact
+	}
+	if isExact {
+		if path != "" {
+			return nil, schema.UnprocessableContentError("exact distinct counts are not supported on nested fields", map[string]any{
+				"value": field,
+			})
+		}
+		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+		if postProcessor.ExactDistinctCounts == nil {
+			postProcessor.ExactDistinctCounts = make(map[string]bool)
+		}
+		postProcessor.ExactDistinctCounts[aggName] = true
+		return map[string]interface{}{
+			"composite": map[string]interface{}{
+				"size": exactDistinctCountPageSize,
+				"sources": []interface{}{
+					map[string]interface{}{
+						"value": map[string]interface{}{
+							"terms": map[string]interface{}{
+								"field": bestFieldOrSubField,
+							},
+						},
+					},
+				},
+			},
+		}, nil
+	}
+
+	cardinality := map[string]interface{}{
+		"field": bestFieldOrSubField,
+	}
+	precisionThreshold := settings.PrecisionThreshold
+	if options.PrecisionThreshold != 0 {
+		precisionThreshold = options.PrecisionThreshold
+	}
+	if precisionThreshold != 0 {
+		cardinality["precision_threshold"] = precisionThreshold
+	}
+	return map[string]interface{}{
+		"cardinality": cardinality,
+	}, nil
+}
+
+// countExactDistinctValues counts the distinct values of the exact distinct counts of a search, whose composite aggregations
+// return the first page of the distinct values. The next pages are requested with the same query until the last page,
+// and the count is set as the value of the aggregation, as returned by the `cardinality` aggregation.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-composite-aggregation.html#_pagination
+func countExactDistinctValues(ctx context.Context, state *types.State, index string, query map[string]interface{}, response map[string]interface{}) error {
+	postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+	aggregations, _ := response["aggregations"].(map[string]interface{})
+	queryAggregations, _ := query["aggs"].(map[string]interface{})
+	for aggName := range postProcessor.ExactDistinctCounts {
+		record, ok := aggregations[aggName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		count, afterKey := countCompositeBuckets(record)
+		aggregation, _ := queryAggregations[aggName].(map[string]interface{})
+		for afterKey != nil {
+			if count >= exactDistinctCountLimit {
+				return schema.UnprocessableContentError(fmt.Sprintf("more than %d distinct values to count exactly, use an approximate distinct count", exactDistinctCountLimit), map[string]any{
+					"value": aggName,
+				})
+			}
+			page, err := state.Client.Search(ctx, index, prepareExactDistinctCountPage(query, aggName, aggregation, afterKey))
+			if err != nil {
+				return schema.UnprocessableContentError("failed to count distinct values", map[string]any{
+					"error": err.Error(),
+				})
+			}
+			pageAggregations, _ := page["aggregations"].(map[string]interface{})
+			pageRecord, _ := pageAggregations[aggName].(map[string]interface{})
+			pageCount, pageAfterKey := countCompositeBuckets(pageRecord)
+			count += pageCount
+			afterKey = pageAfterKey
+		}
+		aggregations[aggName] = map[string]interface{}{
+			"value": count,
+		}
+	}
+	return nil
+}
+
+// countCompositeBuckets returns the number of buckets of a page of a composite aggregation,
+// and the key after which the next page starts, nil if it is the last page.
+func countCompositeBuckets(record map[string]interface{}) (int, interface{}) {
+	buckets, _ := record["buckets"].([]interface{})
+	if len(buckets) < exactDistinctCountPageSize {
+		return len(buckets), nil
+	}
+	return len(buckets), record["after_key"]
+}
+
+// prepareExactDistinctCountPage prepares the search of the page of the distinct values after the given key,
+// which only computes the composite aggregation of the count on the documents matching the query.
+func prepareExactDistinctCountPage(query map[string]interface{}, aggName string, aggregation map[string]interface{}, afterKey interface{}) map[string]interface{} {
+	composite := make(map[string]interface{})
+	if options, ok := aggregation["composite"].(map[string]interface{}); ok {
+		for key, value := range options {
+			composite[key] = value
+		}
+	}
+	composite["after"] = afterKey
+
+	page := map[string]interface{}{
+		"size":             0,
+		"track_total_hits": false,
+		"aggs": map[string]interface{}{
+			aggName: map[string]interface{}{
+				"composite": composite,
+			},
+		},
+	}
+	for _, option := range []string{"query", "runtime_mappings"} {
+		if value, ok := query[option]; ok {
+			page[option] = value
+		}
+	}
+	return page
+}
+
+// getDistinctCountDescription documents the mode of the distinct counts of a field, when the distinct counts of its index are configured.
+func getDistinctCountDescription(settings types.DistinctCountSettings, field string) string {
+	if settings.IsExact(field) {
+		return "Distinct counts are exact."
+	}
+	if settings.PrecisionThreshold == 0 && len(settings.ExactFields) == 0 {
+		return ""
+	}
+	precisionThreshold := settings.PrecisionThreshold
+	if precisionThreshold == 0 {
+		precisionThreshold = defaultPrecisionThreshold
+	}
+	return fmt.Sprintf("Distinct counts are approximate above %d distinct values.", precisionThreshold)
+}
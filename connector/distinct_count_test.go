@@ -0,0 +1,153 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDistinctCountOptions(t *testing.T) {
+	exact := true
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    distinctCountOptions
+		wantErr bool
+	}{
+		{
+			name:  "precision_threshold",
+			value: map[string]interface{}{"precision_threshold": float64(500), "exact": nil},
+			want:  distinctCountOptions{PrecisionThreshold: 500},
+		},
+		{
+			name:  "exact",
+			value: map[string]interface{}{"exact": true},
+			want:  distinctCountOptions{Exact: &exact},
+		},
+		{
+			name:    "precision_threshold_too_high",
+			value:   map[string]interface{}{"precision_threshold": float64(50000)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_exact",
+			value:   map[string]interface{}{"exact": "yes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arguments := map[string]schema.Argument{
+				"distinct_count": {Type: schema.ArgumentTypeLiteral, Value: tt.value},
+			}
+			got, err := getDistinctCountOptions(arguments)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountCompositeBuckets(t *testing.T) {
+	fullPage := make([]interface{}, exactDistinctCountPageSize)
+	afterKey := map[string]interface{}{"value": "s-9999"}
+
+	count, gotAfterKey := countCompositeBuckets(map[string]interface{}{"buckets": fullPage, "after_key": afterKey})
+	assert.Equal(t, exactDistinctCountPageSize, count)
+	assert.Equal(t, afterKey, gotAfterKey)
+
+	// the last page has fewer buckets than the page size, even though the after key is returned
+	count, gotAfterKey = countCompositeBuckets(map[string]interface{}{"buckets": fullPage[:3], "after_key": afterKey})
+	assert.Equal(t, 3, count)
+	assert.Nil(t, gotAfterKey)
+
+	count, gotAfterKey = countCompositeBuckets(nil)
+	assert.Equal(t, 0, count)
+	assert.Nil(t, gotAfterKey)
+}
+
+func TestPrepareExactDistinctCountPage(t *testing.T) {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{"term": map[string]interface{}{"customer_id": "c-1"}},
+		"size":  0,
+		"aggs": map[string]interface{}{
+			"sessions": map[string]interface{}{
+				"composite": map[string]interface{}{
+					"size":    exactDistinctCountPageSize,
+					"sources": []interface{}{map[string]interface{}{"value": map[string]interface{}{"terms": map[string]interface{}{"field": "session_id"}}}},
+				},
+			},
+			"total": map[string]interface{}{"sum": map[string]interface{}{"field": "amount"}},
+		},
+	}
+	aggregation := query["aggs"].(map[string]interface{})["sessions"].(map[string]interface{})
+	page := prepareExactDistinctCountPage(query, "sessions", aggregation, map[string]interface{}{"value": "s-9999"})
+
+	got, err := json.Marshal(page)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "size": 0,
+  "track_total_hits": false,
+  "query": {"term": {"customer_id": "c-1"}},
+  "aggs": {
+    "sessions": {
+      "composite": {
+        "size": 10000,
+        "sources": [{"value": {"terms": {"field": "session_id"}}}],
+        "after": {"value": "s-9999"}
+      }
+    }
+  }
+}`, string(got))
+
+	// the composite aggregation of the query is not changed
+	_, ok := aggregation["composite"].(map[string]interface{})["after"]
+	assert.False(t, ok)
+}
+
+func TestGetDistinctCountDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings types.DistinctCountSettings
+		field    string
+		want     string
+	}{
+		{
+			name:     "not_configured",
+			settings: types.DistinctCountSettings{},
+			field:    "customer_id",
+			want:     "",
+		},
+		{
+			name:     "exact",
+			settings: types.DistinctCountSettings{ExactFields: []string{"session_id"}},
+			field:    "session_id",
+			want:     "Distinct counts are exact.",
+		},
+		{
+			name:     "default_precision_threshold",
+			settings: types.DistinctCountSettings{ExactFields: []string{"session_id"}},
+			field:    "customer_id",
+			want:     "Distinct counts are approximate above 3000 distinct values.",
+		},
+		{
+			name:     "precision_threshold",
+			settings: types.DistinctCountSettings{PrecisionThreshold: 1000},
+			field:    "customer_id",
+			want:     "Distinct counts are approximate above 1000 distinct values.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getDistinctCountDescription(tt.settings, tt.field))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package connector
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
 
 	"github.com/hasura/ndc-elasticsearch/internal"
@@ -27,6 +28,8 @@ func prepareFilterQuery(expression schema.Expression, state *types.State, collec
 		return buildAndClauseQuery(expr.Expressions, state, collection)
 	case *schema.ExpressionOr:
 		return buildOrClauseQuery(expr.Expressions, state, collection)
+	case *schema.ExpressionExists:
+		return handleExpressionExists(expr, state, collection)
 	case *schema.ExpressionNot:
 		res, err := prepareFilterQuery(expr.Expression, state, collection)
 		if err != nil {
@@ -44,8 +47,9 @@ func prepareFilterQuery(expression schema.Expression, state *types.State, collec
 	}
 }
 
-// buildAndClauseQuery constructs an Elasticsearch boolean query with "must" conditions
-// from a list of expressions. In Elasticsearch, "must" conditions are equivalent to AND logic.
+// buildAndClauseQuery constructs an Elasticsearch boolean query with "must" and "filter" conditions
+// from a list of expressions. In Elasticsearch, both "must" and "filter" conditions are equivalent to AND logic.
+// Conditions that contribute to the relevance score are added to "must", the rest are added to "filter".
 //
 // Note: An empty AND clause is treated as a match_all query according to the NDC Spec,
 // which matches all documents in Elasticsearch.
@@ -63,12 +67,108 @@ func buildAndClauseQuery(expressions []schema.Expression, state *types.State, co
 	queries = groupNestedQueriesByPath(queries, "must")
 
 	filter := make(map[string]interface{})
-	filter["bool"] = map[string]interface{}{
-		"must": queries,
-	}
+	filter["bool"] = buildMustClause(queries)
 	return filter, nil
 }
 
+// buildMustClause splits AND-ed queries into the "must" and "filter" clauses of a boolean query.
+//
+// Queries in the "filter" clause are executed in filter context: they don't affect the relevance score
+// and Elasticsearch can cache them. Only full text and fuzzy queries are scored, so everything else is a filter.
+// more reading: https://www.elastic.co/guide/en/elasticsearch/reference/current/query-filter-context.html
+func buildMustClause(queries []map[string]interface{}) map[string]interface{} {
+	must := make([]map[string]interface{}, 0)
+	filter := make([]map[string]interface{}, 0)
+	for _, query := range queries {
+		if isScoringQuery(query) {
+			must = append(must, query)
+		} else {
+			filter = append(filter, query)
+		}
+	}
+
+	boolQuery := make(map[string]interface{})
+	if len(must) != 0 || len(filter) == 0 {
+		boolQuery["must"] = must
+	}
+	if len(filter) != 0 {
+		boolQuery["filter"] = filter
+	}
+	return boolQuery
+}
+
+// isScoringQuery checks whether a query contributes to the relevance score of the matching documents.
+// A compound query is scoring if any of its "must" or "should" clauses is scoring.
+func isScoringQuery(query map[string]interface{}) bool {
+	for queryType, body := range query {
+		switch queryType {
+		case "bool":
+			boolQuery, ok := body.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			for _, clause := range []string{"must", "should"} {
+				for _, clauseQuery := range clauseQueries(boolQuery[clause]) {
+					if isScoringQuery(clauseQuery) {
+						return true
+					}
+				}
+			}
+		case "nested":
+			nestedQuery, ok := body.(map[string]interface{})
+			if !ok {
+				return false
+			}
+			if innerQuery, ok := nestedQuery["query"].(map[string]interface{}); ok && isScoringQuery(innerQuery) {
+				return true
+			}
+		default:
+			if internal.FullTextQueries[queryType] || queryType == "fuzzy" || queryType == "multi_match" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clauseQueries returns the queries of a boolean query clause, which can either be a single query or a list of queries.
+func clauseQueries(clause interface{}) []map[string]interface{} {
+	switch queries := clause.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{queries}
+	case []map[string]interface{}:
+		return queries
+	case []interface{}:
+		result := make([]map[string]interface{}, 0, len(queries))
+		for _, query := range queries {
+			if queryMap, ok := query.(map[string]interface{}); ok {
+				result = append(result, queryMap)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// toFilterContext wraps a non-scoring query in the "filter" clause of a boolean query,
+// so that it is executed in filter context. Scoring queries are returned as is.
+func toFilterContext(query map[string]interface{}) map[string]interface{} {
+	if isScoringQuery(query) {
+		return query
+	}
+	if boolQuery, ok := query["bool"].(map[string]interface{}); ok {
+		if _, hasShould := boolQuery["should"]; !hasShould {
+			// a non-scoring boolean query without "should" is already executed in filter context
+			return query
+		}
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []map[string]interface{}{query},
+		},
+	}
+}
+
 // buildOrClauseQuery constructs an Elasticsearch boolean query with "should" conditions
 // from a list of expressions. In Elasticsearch, "should" conditions are equivalent to OR logic.
 func buildOrClauseQuery(expressions []schema.Expression, state *types.State, collection string) (map[string]interface{}, error) {
@@ -118,6 +218,10 @@ func getPredicate(expression schema.Expression) (string, schema.Expression) {
 		}
 
 		columnPathPostfix, predicate := getPredicate(expressionPredicate)
+		if !isComparisonExpression(predicate) {
+			// the predicate is a boolean expression, which is handled as a whole by handleExpressionExists
+			return "", expression
+		}
 		return fmt.Sprintf("%s.%s", fieldName, columnPathPostfix), predicate
 	}
 	switch expr := expression.Interface().(type) {
@@ -130,6 +234,15 @@ func getPredicate(expression schema.Expression) (string, schema.Expression) {
 	return "", expression
 }
 
+// isComparisonExpression checks if an expression is a unary or binary comparison.
+func isComparisonExpression(expression schema.Expression) bool {
+	switch expression.Interface().(type) {
+	case *schema.ExpressionUnaryComparisonOperator, *schema.ExpressionBinaryComparisonOperator:
+		return true
+	}
+	return false
+}
+
 func requiresNestedFiltering(predicate schema.Expression) (requiresNestedFiltering bool, nestedFieldName string) {
 	inCollection, ok := predicate["in_collection"].(schema.ExistsInCollection)
 	if !ok {
@@ -145,6 +258,147 @@ func requiresNestedFiltering(predicate schema.Expression) (requiresNestedFilteri
 	return false, ""
 }
 
+// handleExpressionExists handles an exists expression over a nested collection (an array of nested or object fields).
+//
+// The whole predicate is executed inside a single nested query, so that all of its conditions
+// apply to the same element of the array. Object arrays are flattened by Elasticsearch,
+// so their conditions can match different elements.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/nested.html
+func handleExpressionExists(expr *schema.ExpressionExists, state *types.State, collection string) (map[string]interface{}, error) {
+	nestedCollection, err := expr.InCollection.AsNestedCollection()
+	if err != nil {
+		return nil, schema.UnprocessableContentError("exists is only supported on nested collections", map[string]any{
+			"in_collection": expr.InCollection,
+		})
+	}
+
+	fieldPath := strings.Join(append(nestedCollection.FieldPath, nestedCollection.ColumnName), ".")
+	isNested, err := state.Configuration.IsFieldNested(collection, fieldPath)
+	if err != nil {
+		return nil, schema.UnprocessableContentError("unable to get field types", map[string]any{
+			"fieldPath": fieldPath,
+			"index":     collection,
+		})
+	}
+
+	var filter map[string]interface{}
+	if expr.Predicate == nil {
+		// without a predicate, any element of the array matches
+		if isNested {
+			filter = map[string]interface{}{"match_all": map[string]interface{}{}}
+		} else {
+			filter = map[string]interface{}{"exists": map[string]interface{}{"field": fieldPath}}
+		}
+	} else {
+		predicate, err := scopeExpression(expr.Predicate, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		filter, err = prepareFilterQuery(predicate, state, collection)
+		if err != nil {
+			return nil, err
+		}
+		// the comparisons are wrapped in nested queries for the path of the collection and its parents,
+		// which are replaced by the single nested query below
+		filter = unwrapNestedQueries(filter, fieldPath)
+	}
+
+	return prepareNestedQuery(state, filter, fieldPath, collection)
+}
+
+// scopeExpression scopes the comparisons in an expression to the path of the nested collection they belong to.
+// The columns of nested exists expressions are prefixed with the path, and their predicates are scoped when they are handled.
+func scopeExpression(expression schema.Expression, fieldPath string) (schema.Expression, error) {
+	switch expr := expression.Interface().(type) {
+	case *schema.ExpressionUnaryComparisonOperator, *schema.ExpressionBinaryComparisonOperator:
+		// a comparison is scoped with a single-comparison exists expression, which is resolved by getPredicate
+		return schema.NewExpressionExists(expr, schema.NewExistsInCollectionNestedCollection(fieldPath, nil, nil)).Encode(), nil
+	case *schema.ExpressionAnd:
+		expressions, err := scopeExpressions(expr.Expressions, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		expr.Expressions = expressions
+		return expr.Encode(), nil
+	case *schema.ExpressionOr:
+		expressions, err := scopeExpressions(expr.Expressions, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		expr.Expressions = expressions
+		return expr.Encode(), nil
+	case *schema.ExpressionNot:
+		scoped, err := scopeExpression(expr.Expression, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		expr.Expression = scoped
+		return expr.Encode(), nil
+	case *schema.ExpressionExists:
+		nestedCollection, err := expr.InCollection.AsNestedCollection()
+		if err != nil {
+			return nil, schema.UnprocessableContentError("exists is only supported on nested collections", map[string]any{
+				"in_collection": expr.InCollection,
+			})
+		}
+		nestedCollection.ColumnName = strings.Join(append([]string{fieldPath}, append(nestedCollection.FieldPath, nestedCollection.ColumnName)...), ".")
+		nestedCollection.FieldPath = nil
+		expr.InCollection = nestedCollection.Encode()
+		return expr.Encode(), nil
+	}
+
+	return nil, schema.UnprocessableContentError("invalid predicate type", map[string]any{
+		"expression": expression,
+	})
+}
+
+// scopeExpressions scopes a list of expressions, see scopeExpression.
+func scopeExpressions(expressions []schema.Expression, fieldPath string) ([]schema.Expression, error) {
+	scoped := make([]schema.Expression, 0, len(expressions))
+	for _, expression := range expressions {
+		scopedExpression, err := scopeExpression(expression, fieldPath)
+		if err != nil {
+			return nil, err
+		}
+		scoped = append(scoped, scopedExpression)
+	}
+	return scoped, nil
+}
+
+// unwrapNestedQueries replaces the nested queries for the given path, or any of its parents, with their inner query.
+func unwrapNestedQueries(query map[string]interface{}, fieldPath string) map[string]interface{} {
+	if nested, ok := query["nested"].(map[string]interface{}); ok {
+		path, _ := nested["path"].(string)
+		innerQuery, ok := nested["query"].(map[string]interface{})
+		if ok && (path == fieldPath || strings.HasPrefix(fieldPath, path+".")) {
+			return unwrapNestedQueries(innerQuery, fieldPath)
+		}
+		return query
+	}
+
+	boolQuery, ok := query["bool"].(map[string]interface{})
+	if !ok {
+		return query
+	}
+
+	unwrapped := make(map[string]interface{}, len(boolQuery))
+	for clause, clauseQuery := range boolQuery {
+		switch clauseQuery := clauseQuery.(type) {
+		case map[string]interface{}:
+			unwrapped[clause] = unwrapNestedQueries(clauseQuery, fieldPath)
+		case []map[string]interface{}:
+			queries := make([]map[string]interface{}, 0, len(clauseQuery))
+			for _, q := range clauseQuery {
+				queries = append(queries, unwrapNestedQueries(q, fieldPath))
+			}
+			unwrapped[clause] = queries
+		default:
+			unwrapped[clause] = clauseQuery
+		}
+	}
+	return map[string]interface{}{"bool": unwrapped}
+}
+
 // handleExpressionUnaryComparisonOperator processes the unary comparison operator expression.
 func handleExpressionUnaryComparisonOperator(expr *schema.ExpressionUnaryComparisonOperator, state *types.State, collection string) (map[string]interface{}, error) {
 	if expr.Operator == "is_null" {
@@ -182,6 +436,11 @@ func handleExpressionBinaryComparisonOperator(
 	}
 
 	fieldPath := strings.Join(expr.Column.FieldPath, ".")
+	if fieldPath == "_id" {
+		// `_id` is a metadata field, so it is not part of the mappings
+		return handleIdComparisonOperator(expr)
+	}
+
 	fieldType, fieldSubTypes, _, err := state.Configuration.GetFieldProperties(collection, fieldPath)
 	if err != nil {
 		return nil, schema.UnprocessableContentError("unable to get field types", map[string]any{
@@ -190,6 +449,22 @@ func handleExpressionBinaryComparisonOperator(
 		})
 	}
 
+	if relation, ok := internal.RangeRelationOperators[expr.Operator]; ok && internal.RangeFieldTypes[fieldType] != "" {
+		return handleRangeRelationOperator(expr, state, collection, fieldPath, relation)
+	}
+
+	if expr.Operator == "search_as_you_type" {
+		return handleSearchAsYouTypeOperator(expr, state, collection, fieldPath, fieldType, fieldSubTypes)
+	}
+
+	if query, ok := internal.StandardComparisonOperators[expr.Operator]; ok {
+		return handleStandardComparisonOperator(expr, state, collection, fieldPath, fieldType, fieldSubTypes, query)
+	}
+
+	if query, ok := internal.OptionsQueries[expr.Operator]; ok {
+		return handleOptionsOperator(expr, state, collection, fieldPath, fieldType, fieldSubTypes, query)
+	}
+
 	bestFieldOrSubField, operatorFound := internal.GetBestFieldOrSubFieldForQuery(fieldPath, fieldType, fieldSubTypes, expr.Operator)
 	if !operatorFound {
 		return nil, schema.UnprocessableContentError("invalid binary comaparison operator", map[string]any{
@@ -202,8 +477,16 @@ func handleExpressionBinaryComparisonOperator(
 		return nil, err
 	}
 
+	operator := expr.Operator
+	if fieldType == "ip" {
+		operator, err = prepareIpComparison(expr.Operator, value[bestFieldOrSubField])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	filter := map[string]interface{}{
-		expr.Operator: value,
+		operator: value,
 	}
 
 	filter, err = prepareNestedQuery(state, filter, fieldPath, collection)
@@ -215,6 +498,358 @@ func handleExpressionBinaryComparisonOperator(
 	return filter, nil
 }
 
+// handleRangeRelationOperator processes the relation operators (contains, within, intersects) of range fields.
+// It generates a range query with the `relation` parameter set, which compares the range of the field with the given bounds.
+func handleRangeRelationOperator(
+	expr *schema.ExpressionBinaryComparisonOperator,
+	state *types.State,
+	collection string,
+	fieldPath string,
+	relation string,
+) (map[string]interface{}, error) {
+	var value interface{}
+	switch compValue := expr.Value.Interface().(type) {
+	case *schema.ComparisonValueScalar:
+		bounds, err := processRangeValue(compValue.Value)
+		if err != nil {
+			return nil, err
+		}
+		bounds["relation"] = relation
+		value = bounds
+	case *schema.ComparisonValueVariable:
+		value = types.RangeRelationVariable{
+			Name:     types.Variable(compValue.Name),
+			Relation: relation,
+		}
+	default:
+		return nil, schema.UnprocessableContentError("invalid type of comparison value", map[string]any{
+			"value": expr.Value["type"],
+		})
+	}
+
+	filter := map[string]interface{}{
+		"range": map[string]interface{}{
+			fieldPath: value,
+		},
+	}
+
+	return prepareNestedQuery(state, filter, fieldPath, collection)
+}
+
+// handleSearchAsYouTypeOperator handles the `search_as_you_type` operator, which matches the terms of the value in order,
+// the last term being a prefix. It generates a `bool_prefix` multi_match query over the search_as_you_type field
+// and its shingle and prefix subfields, which are created by Elasticsearch for the field.
+// The search_as_you_type subfield of a text field is used if the field itself is not of the search_as_you_type type.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-as-you-type.html
+func handleSearchAsYouTypeOperator(
+	expr *schema.ExpressionBinaryComparisonOperator,
+	state *types.State,
+	collection string,
+	fieldPath string,
+	fieldType string,
+	fieldSubTypes map[string]string,
+) (map[string]interface{}, error) {
+	searchAsYouTypeField := fieldPath
+	if fieldType != "search_as_you_type" {
+		subField, ok := fieldSubTypes["search_as_you_type"]
+		if !ok {
+			return nil, schema.UnprocessableContentError("invalid binary comaparison operator", map[string]any{
+				"expression": expr.Operator,
+			})
+		}
+		searchAsYouTypeField = fieldPath + "." + subField
+	}
+
+	fieldMap, err := state.Configuration.GetFieldMap(collection, fieldPath)
+	if err != nil {
+		return nil, schema.UnprocessableContentError("unable to get field types", map[string]any{
+			"fieldPath": fieldPath,
+			"index":     collection,
+		})
+	}
+	if searchAsYouTypeField != fieldPath {
+		fieldMap, _ = fieldMap["fields"].(map[string]interface{})[fieldSubTypes["search_as_you_type"]].(map[string]interface{})
+	}
+
+	value, err := evalComparisonValue(expr.Value, "query", expr.Operator)
+	if err != nil {
+		return nil, err
+	}
+
+	value["type"] = "bool_prefix"
+	value["fields"] = getSearchAsYouTypeFields(searchAsYouTypeField, fieldMap)
+	filter := map[string]interface{}{
+		"multi_match": value,
+	}
+
+	return prepareNestedQuery(state, filter, fieldPath, collection)
+}
+
+// getSearchAsYouTypeFields returns the search_as_you_type field and its subfields:
+// a `._<n>gram` shingle subfield for each shingle size up to `max_shingle_size` (3 by default), and the `._index_prefix` subfield.
+func getSearchAsYouTypeFields(fieldPath string, fieldMap map[string]interface{}) []string {
+	maxShingleSize := 3
+	if size, ok := fieldMap["max_shingle_size"].(float64); ok {
+		maxShingleSize = int(size)
+	}
+
+	fields := []string{fieldPath}
+	for shingleSize := 2; shingleSize <= maxShingleSize; shingleSize++ {
+		fields = append(fields, fmt.Sprintf("%s._%dgram", fieldPath, shingleSize))
+	}
+	return append(fields, fieldPath+"._index_prefix")
+}
+
+// handleIdComparisonOperator handles the comparison operators of the `_id` column.
+// The equality operators are translated into an `ids` query, which is the fastest way to fetch documents by id.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-ids-query.html
+func handleIdComparisonOperator(expr *schema.ExpressionBinaryComparisonOperator) (map[string]interface{}, error) {
+	var value interface{}
+	switch compValue := expr.Value.Interface().(type) {
+	case *schema.ComparisonValueScalar:
+		value = compValue.Value
+	case *schema.ComparisonValueVariable:
+		value = types.Variable(compValue.Name)
+	default:
+		return nil, schema.UnprocessableContentError("invalid type of comparison value", map[string]any{
+			"value": expr.Value["type"],
+		})
+	}
+
+	switch expr.Operator {
+	case "term", "_eq":
+		return map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": []interface{}{value},
+			},
+		}, nil
+	case "_neq":
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []map[string]interface{}{
+					{
+						"ids": map[string]interface{}{
+							"values": []interface{}{value},
+						},
+					},
+				},
+			},
+		}, nil
+	case "terms", "_in":
+		return map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": value,
+			},
+		}, nil
+	case "prefix":
+		return map[string]interface{}{
+			"prefix": map[string]interface{}{
+				"_id": value,
+			},
+		}, nil
+	}
+
+	return nil, schema.UnprocessableContentError("invalid binary comaparison operator", map[string]any{
+		"expression": expr.Operator,
+	})
+}
+
+// handleStandardComparisonOperator handles the standard comparison operators (`_eq`, `_in`, `_gt`, `_like`, `_ieq`...),
+// by translating them into the given Elasticsearch query on the best field or subfield for it.
+func handleStandardComparisonOperator(
+	expr *schema.ExpressionBinaryComparisonOperator,
+	state *types.State,
+	collection string,
+	fieldPath string,
+	fieldType string,
+	fieldSubTypes map[string]string,
+	query string,
+) (map[string]interface{}, error) {
+	bestFieldOrSubField, operatorFound := internal.GetBestFieldOrSubFieldForQuery(fieldPath, fieldType, fieldSubTypes, query)
+	if !operatorFound {
+		return nil, schema.UnprocessableContentError("invalid binary comaparison operator", map[string]any{
+			"expression": expr.Operator,
+		})
+	}
+
+	var value interface{}
+	switch compValue := expr.Value.Interface().(type) {
+	case *schema.ComparisonValueScalar:
+		value = compValue.Value
+	case *schema.ComparisonValueVariable:
+		value = types.Variable(compValue.Name)
+	default:
+		return nil, schema.UnprocessableContentError("invalid type of comparison value", map[string]any{
+			"value": expr.Value["type"],
+		})
+	}
+
+	if fieldType == "ip" {
+		ipQuery := query
+		if query == "range" {
+			// the bound of a range is a single IP address
+			ipQuery = "term"
+		}
+		if _, err := prepareIpComparison(ipQuery, value); err != nil {
+			return nil, err
+		}
+	}
+
+	switch query {
+	case "range":
+		// `_gt` -> `gt`, `_lte` -> `lte`...
+		value = map[string]interface{}{
+			strings.TrimPrefix(expr.Operator, "_"): value,
+		}
+	case "wildcard":
+		pattern, err := likeToWildcardPattern(value)
+		if err != nil {
+			return nil, err
+		}
+		value = pattern
+	}
+
+	if query == "wildcard" || internal.CaseInsensitiveOperators[expr.Operator] {
+		// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-term-query.html#term-field-params
+		options := map[string]interface{}{
+			"value": value,
+		}
+		if internal.CaseInsensitiveOperators[expr.Operator] {
+			options["case_insensitive"] = true
+		}
+		value = options
+	}
+
+	filter := map[string]interface{}{
+		query: map[string]interface{}{
+			bestFieldOrSubField: value,
+		},
+	}
+
+	if expr.Operator == "_neq" {
+		filter = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []map[string]interface{}{filter},
+			},
+		}
+	}
+
+	return prepareNestedQuery(state, filter, fieldPath, collection)
+}
+
+// likeToWildcardPattern converts the pattern of a `_like` comparison into the pattern of a wildcard query.
+// `%` matches zero or more characters and `_` matches a single character. They can be escaped with a backslash.
+// Variables are converted once they are replaced.
+func likeToWildcardPattern(value interface{}) (interface{}, error) {
+	if variable, ok := value.(types.Variable); ok {
+		return types.LikePatternVariable(variable), nil
+	}
+
+	pattern, ok := value.(string)
+	if !ok {
+		return nil, schema.UnprocessableContentError("invalid like pattern", map[string]any{
+			"value": value,
+		})
+	}
+
+	var wildcard strings.Builder
+	escaped := false
+	for _, char := range pattern {
+		switch {
+		case escaped:
+			if char == '*' || char == '?' || char == '\\' {
+				wildcard.WriteRune('\\')
+			}
+			wildcard.WriteRune(char)
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '%':
+			wildcard.WriteRune('*')
+		case char == '_':
+			wildcard.WriteRune('?')
+		case char == '*' || char == '?':
+			wildcard.WriteRune('\\')
+			wildcard.WriteRune(char)
+		default:
+			wildcard.WriteRune(char)
+		}
+	}
+	if escaped {
+		// a trailing backslash matches itself
+		wildcard.WriteString(`\\`)
+	}
+
+	return wildcard.String(), nil
+}
+
+// handleOptionsOperator handles the comparison operators that take an object of options as argument,
+// like `fuzzy` or `intervals`. The options are passed to the Elasticsearch query as is, without the unset ones.
+func handleOptionsOperator(
+	expr *schema.ExpressionBinaryComparisonOperator,
+	state *types.State,
+	collection string,
+	fieldPath string,
+	fieldType string,
+	fieldSubTypes map[string]string,
+	query string,
+) (map[string]interface{}, error) {
+	bestFieldOrSubField, operatorFound := internal.GetBestFieldOrSubFieldForQuery(fieldPath, fieldType, fieldSubTypes, query)
+	if !operatorFound {
+		return nil, schema.UnprocessableContentError("invalid binary comaparison operator", map[string]any{
+			"expression": expr.Operator,
+		})
+	}
+
+	var value interface{}
+	switch compValue := expr.Value.Interface().(type) {
+	case *schema.ComparisonValueScalar:
+		options, ok := removeNullOptions(compValue.Value).(map[string]interface{})
+		if !ok {
+			return nil, schema.UnprocessableContentError("expected an object of options", map[string]any{
+				"operator": expr.Operator,
+				"value":    compValue.Value,
+			})
+		}
+		value = options
+	case *schema.ComparisonValueVariable:
+		value = types.Variable(compValue.Name)
+	default:
+		return nil, schema.UnprocessableContentError("invalid type of comparison value", map[string]any{
+			"value": expr.Value["type"],
+		})
+	}
+
+	filter := map[string]interface{}{
+		query: map[string]interface{}{
+			bestFieldOrSubField: value,
+		},
+	}
+
+	return prepareNestedQuery(state, filter, fieldPath, collection)
+}
+
+// removeNullOptions recursively removes the options with a null value, as Elasticsearch rejects them.
+func removeNullOptions(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		options := make(map[string]interface{}, len(v))
+		for key, option := range v {
+			if option != nil {
+				options[key] = removeNullOptions(option)
+			}
+		}
+		return options
+	case []interface{}:
+		options := make([]interface{}, 0, len(v))
+		for _, option := range v {
+			options = append(options, removeNullOptions(option))
+		}
+		return options
+	}
+	return value
+}
+
 // joinFieldPath joins the fieldPath and columnName to form a fully qualified field path.
 // It also checks if the field is nested and returns the nested path.
 func joinFieldPath(state *types.State, fieldPath []string, columnName string, collection string) (string, string) {
@@ -294,13 +929,17 @@ func groupNestedQueriesByPath(queries []map[string]interface{}, boolClause strin
 				},
 			})
 		} else {
+			innerBool := map[string]interface{}{
+				boolClause: s.innerQueries,
+			}
+			if boolClause == "must" {
+				innerBool = buildMustClause(s.innerQueries)
+			}
 			result = append(result, map[string]interface{}{
 				"nested": map[string]interface{}{
 					"path": s.path,
 					"query": map[string]interface{}{
-						"bool": map[string]interface{}{
-							boolClause: s.innerQueries,
-						},
+						"bool": innerBool,
 					},
 				},
 			})
@@ -378,3 +1017,71 @@ func processRangeValue(rangeValue interface{}) (map[string]interface{}, error) {
 
 	return rangeMap, nil
 }
+
+// prepareIpComparison validates the IP literals of a comparison on an ip field before they are sent to Elasticsearch,
+// and returns the Elasticsearch query for the operator. `in_cidr` is executed as a term query with a CIDR block.
+// Variables are not validated, as their values are only known at execution time.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ip.html#query-ip-fields
+func prepareIpComparison(operator string, value interface{}) (string, error) {
+	if _, ok := value.(types.Variable); ok {
+		if operator == "in_cidr" {
+			return "term", nil
+		}
+		return operator, nil
+	}
+
+	switch operator {
+	case "term":
+		return operator, validateIpAddress(value)
+	case "terms":
+		values, ok := value.([]interface{})
+		if !ok {
+			return "", schema.UnprocessableContentError("expected an array of IP addresses", map[string]any{
+				"value": value,
+			})
+		}
+		for _, ip := range values {
+			if err := validateIpAddress(ip); err != nil {
+				return "", err
+			}
+		}
+		return operator, nil
+	case "range":
+		bounds, ok := value.(map[string]interface{})
+		if !ok {
+			return "", schema.UnprocessableContentError("expected an object of IP address bounds", map[string]any{
+				"value": value,
+			})
+		}
+		for key, bound := range bounds {
+			if key == "boost" {
+				continue
+			}
+			if err := validateIpAddress(bound); err != nil {
+				return "", err
+			}
+		}
+		return operator, nil
+	case "in_cidr":
+		cidr, ok := value.(string)
+		if _, err := netip.ParsePrefix(cidr); !ok || err != nil {
+			return "", schema.UnprocessableContentError("invalid CIDR block", map[string]any{
+				"value": value,
+			})
+		}
+		return "term", nil
+	}
+
+	return operator, nil
+}
+
+// validateIpAddress checks that the value is a valid IPv4 or IPv6 address.
+func validateIpAddress(value interface{}) error {
+	ip, ok := value.(string)
+	if _, err := netip.ParseAddr(ip); !ok || err != nil {
+		return schema.UnprocessableContentError("invalid IP address", map[string]any{
+			"value": value,
+		})
+	}
+	return nil
+}
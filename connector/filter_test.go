@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hasura/ndc-elasticsearch/types"
 	"github.com/hasura/ndc-sdk-go/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -122,3 +123,81 @@ func TestRequiresNestedFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareIpComparison(t *testing.T) {
+	tests := []struct {
+		name         string
+		operator     string
+		value        interface{}
+		wantOperator string
+		wantErr      bool
+	}{
+		{name: "term_ipv4", operator: "term", value: "192.168.1.1", wantOperator: "term"},
+		{name: "term_ipv6", operator: "term", value: "2001:db8::1", wantOperator: "term"},
+		{name: "term_invalid", operator: "term", value: "192.168.1.256", wantErr: true},
+		{name: "terms_invalid", operator: "terms", value: []interface{}{"10.0.0.1", "localhost"}, wantErr: true},
+		{name: "range", operator: "range", value: map[string]interface{}{"gte": "10.0.0.0", "lt": "10.0.1.0"}, wantOperator: "range"},
+		{name: "range_invalid", operator: "range", value: map[string]interface{}{"gte": 10}, wantErr: true},
+		{name: "range_not_object", operator: "range", value: "10.0.0.0", wantErr: true},
+		{name: "in_cidr", operator: "in_cidr", value: "2001:db8::/32", wantOperator: "term"},
+		{name: "in_cidr_invalid", operator: "in_cidr", value: "10.0.0.1", wantErr: true},
+		{name: "in_cidr_variable", operator: "in_cidr", value: types.Variable("$cidr"), wantOperator: "term"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator, err := prepareIpComparison(tt.operator, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOperator, operator)
+		})
+	}
+}
+
+func TestHandleStandardComparisonOperatorWithoutField(t *testing.T) {
+	expr := &schema.ExpressionBinaryComparisonOperator{
+		Operator: "_like",
+		Value:    schema.NewComparisonValueScalar("10.0.*").Encode(),
+	}
+	_, err := handleStandardComparisonOperator(expr, nil, "logs", "client_ip", "ip", nil, "wildcard")
+	assert.Error(t, err)
+}
+
+func TestHandleOptionsOperatorWithoutField(t *testing.T) {
+	expr := &schema.ExpressionBinaryComparisonOperator{
+		Operator: "fuzzy",
+		Value:    schema.NewComparisonValueScalar(map[string]interface{}{"value": "10.0.0.1"}).Encode(),
+	}
+	_, err := handleOptionsOperator(expr, nil, "logs", "client_ip", "ip", nil, "fuzzy")
+	assert.Error(t, err)
+}
+
+func TestLikeToWildcardPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    interface{}
+	}{
+		{pattern: "john%", want: "john*"},
+		{pattern: "_ohn", want: "?ohn"},
+		{pattern: `100\%`, want: "100%"},
+		{pattern: `snake\_case`, want: "snake_case"},
+		{pattern: "what?*", want: `what\?\*`},
+		{pattern: `back\\slash`, want: `back\\slash`},
+		{pattern: `trailing\`, want: `trailing\\`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := likeToWildcardPattern(tt.pattern)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := likeToWildcardPattern(types.Variable("$pattern"))
+	assert.NoError(t, err)
+	assert.Equal(t, types.LikePatternVariable("$pattern"), got)
+}
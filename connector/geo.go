@@ -0,0 +1,148 @@
+package connector
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// geohashAlphabet is the base 32 alphabet of geohashes.
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geoJSONPoint returns a GeoJSON Point geometry, whose coordinates are the longitude and the latitude of the point.
+// https://datatracker.ietf.org/doc/html/rfc7946#section-3.1.2
+func geoJSONPoint(lon, lat float64) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "Point",
+		"coordinates": []interface{}{lon, lat},
+	}
+}
+
+// geoJSONBoundingBox returns a GeoJSON Polygon geometry of a bounding box, with its bbox.
+// The ring of the polygon is counterclockwise, as required by RFC 7946.
+// https://datatracker.ietf.org/doc/html/rfc7946#section-3.1.6
+func geoJSONBoundingBox(west, south, east, north float64) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "Polygon",
+		"bbox": []interface{}{west, south, east, north},
+		"coordinates": []interface{}{
+			[]interface{}{
+				[]interface{}{west, south},
+				[]interface{}{east, south},
+				[]interface{}{east, north},
+				[]interface{}{west, north},
+				[]interface{}{west, south},
+			},
+		},
+	}
+}
+
+// getGeoLocation returns the longitude and the latitude of a location returned by Elasticsearch, e.g. `{"lat": 48.86, "lon": 2.35}`.
+func getGeoLocation(value interface{}) (float64, float64, bool) {
+	location, ok := value.(map[string]interface{})
+	if !ok {
+		return 0, 0, false
+	}
+	lon, lonOk := location["lon"].(float64)
+	lat, latOk := location["lat"].(float64)
+	return lon, lat, lonOk && latOk
+}
+
+// extractGeoBounds converts the result of a `geo_bounds` aggregation into a GeoJSON Polygon, null if there are no locations.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-geobounds-aggregation.html
+func extractGeoBounds(record map[string]interface{}) interface{} {
+	bounds, _ := record["bounds"].(map[string]interface{})
+	west, north, topLeftOk := getGeoLocation(bounds["top_left"])
+	east, south, bottomRightOk := getGeoLocation(bounds["bottom_right"])
+	if !topLeftOk || !bottomRightOk {
+		return nil
+	}
+	return geoJSONBoundingBox(west, south, east, north)
+}
+
+// extractGeoCentroid converts the result of a `geo_centroid` aggregation into a GeoJSON Point, null if there are no locations.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-geocentroid-aggregation.html
+func extractGeoCentroid(record map[string]interface{}) interface{} {
+	lon, lat, ok := getGeoLocation(record["location"])
+	if !ok {
+		return nil
+	}
+	return geoJSONPoint(lon, lat)
+}
+
+// getGeoGridCellBounds returns the bounding box of a cell of a geohash or map tile grid, as a GeoJSON Polygon.
+// The bounds of H3 cells are not returned, as they are hexagons which can't be computed without the H3 library.
+func getGeoGridCellBounds(grid string, key interface{}) interface{} {
+	cell, _ := key.(string)
+	var west, south, east, north float64
+	var ok bool
+	switch grid {
+	case "geohash_grid":
+		west, south, east, north, ok = decodeGeohash(cell)
+	case "geotile_grid":
+		west, south, east, north, ok = decodeGeotile(cell)
+	}
+	if !ok {
+		return nil
+	}
+	return geoJSONBoundingBox(west, south, east, north)
+}
+
+// decodeGeohash returns the bounds of a geohash, whose bits alternately halve the longitude and the latitude intervals.
+// https://en.wikipedia.org/wiki/Geohash
+func decodeGeohash(geohash string) (west, south, east, north float64, ok bool) {
+	if geohash == "" {
+		return 0, 0, 0, 0, false
+	}
+	west, south, east, north = -180, -90, 180, 90
+	isLon := true
+	for _, char := range geohash {
+		bits := strings.IndexRune(geohashAlphabet, char)
+		if bits < 0 {
+			return 0, 0, 0, 0, false
+		}
+		for mask := 16; mask != 0; mask >>= 1 {
+			if isLon {
+				mid := (west + east) / 2
+				if bits&mask != 0 {
+					west = mid
+				} else {
+					east = mid
+				}
+			} else {
+				mid := (south + north) / 2
+				if bits&mask != 0 {
+					south = mid
+				} else {
+					north = mid
+				}
+			}
+			isLon = !isLon
+		}
+	}
+	return west, south, east, north, true
+}
+
+// decodeGeotile returns the bounds of a `zoom/x/y` map tile, in the Web Mercator projection.
+// https://wiki.openstreetmap.org/wiki/Slippy_map_tilenames
+func decodeGeotile(tile string) (west, south, east, north float64, ok bool) {
+	parts := strings.Split(tile, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, 0, false
+	}
+	coordinates := make([]int, len(parts))
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, 0, 0, 0, false
+		}
+		coordinates[i] = value
+	}
+
+	tiles := math.Exp2(float64(coordinates[0]))
+	x, y := float64(coordinates[1]), float64(coordinates[2])
+	tileLat := func(y float64) float64 {
+		return math.Atan(math.Sinh(math.Pi*(1-2*y/tiles))) * 180 / math.Pi
+	}
+	return x/tiles*360 - 180, tileLat(y + 1), (x+1)/tiles*360 - 180, tileLat(y), true
+}
@@ -0,0 +1,130 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGeoGridCellBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		grid string
+		key  interface{}
+		want []float64
+	}{
+		{
+			name: "geohash",
+			grid: "geohash_grid",
+			key:  "s",
+			want: []float64{0, 0, 45, 45},
+		},
+		{
+			name: "geohash_precision_2",
+			grid: "geohash_grid",
+			key:  "u0",
+			want: []float64{0, 45, 11.25, 50.625},
+		},
+		{
+			name: "geotile_world",
+			grid: "geotile_grid",
+			key:  "0/0/0",
+			want: []float64{-180, -85.0511287798066, 180, 85.0511287798066},
+		},
+		{
+			name: "geotile",
+			grid: "geotile_grid",
+			key:  "1/1/0",
+			want: []float64{0, 0, 180, 85.0511287798066},
+		},
+		{
+			name: "geohex",
+			grid: "geohex_grid",
+			key:  "841f91dffffffff",
+			want: nil,
+		},
+		{
+			name: "invalid_geohash",
+			grid: "geohash_grid",
+			key:  "sa",
+			want: nil,
+		},
+		{
+			name: "invalid_geotile",
+			grid: "geotile_grid",
+			key:  "1/x/0",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds := getGeoGridCellBounds(tt.grid, tt.key)
+			if tt.want == nil {
+				assert.Nil(t, bounds)
+				return
+			}
+			bbox := bounds.(map[string]interface{})["bbox"].([]interface{})
+			assert.Len(t, bbox, 4)
+			for i, want := range tt.want {
+				assert.InDelta(t, want, bbox[i], 1e-9)
+			}
+		})
+	}
+}
+
+func TestExtractGeoAggregations(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		record   string
+		want     string
+	}{
+		{
+			name:     "geo_bounds",
+			function: "geo_bounds",
+			record:   `{"bounds": {"top_left": {"lat": 48.9, "lon": 2.2}, "bottom_right": {"lat": 48.8, "lon": 2.4}}}`,
+			want: `{
+  "type": "Polygon",
+  "bbox": [2.2, 48.8, 2.4, 48.9],
+  "coordinates": [[[2.2, 48.8], [2.4, 48.8], [2.4, 48.9], [2.2, 48.9], [2.2, 48.8]]]
+}`,
+		},
+		{
+			name:     "geo_bounds_without_locations",
+			function: "geo_bounds",
+			record:   `{}`,
+			want:     `null`,
+		},
+		{
+			name:     "geo_centroid",
+			function: "geo_centroid",
+			record:   `{"location": {"lat": 48.85, "lon": 2.35}, "count": 3}`,
+			want:     `{"type": "Point", "coordinates": [2.35, 48.85]}`,
+		},
+		{
+			name:     "geo_centroid_without_locations",
+			function: "geo_centroid",
+			record:   `{"count": 0}`,
+			want:     `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var record map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.record), &record))
+
+			var got interface{}
+			if tt.function == "geo_bounds" {
+				got = extractGeoBounds(record)
+			} else {
+				got = extractGeoCentroid(record)
+			}
+			gotJSON, err := json.Marshal(got)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(gotJSON))
+		})
+	}
+}
@@ -0,0 +1,768 @@
+package connector
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hasura/ndc-elasticsearch/elasticsearch"
+	"github.com/hasura/ndc-elasticsearch/internal"
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// groupsAggregationName is the name of the aggregation which groups the documents of a query with the `group_by` argument.
+const groupsAggregationName = "_groups"
+
+// cellCentroidAggregationPrefix is the prefix of the name of the aggregations which compute the centroid of the locations of each group,
+// for the dimensions grouped by geo grid cells.
+const cellCentroidAggregationPrefix = "_centroid."
+
+// groupDimension is a dimension of the `group_by` argument.
+type groupDimension struct {
+	column        string
+	field         string
+	fieldType     string
+	order         string
+	missingBucket bool
+	// groupsType is the type of the aggregation which groups the values of the dimension:
+	// terms, date_histogram, histogram, range, date_range and ip_range for the dimensions grouped by ranges,
+	// or geohash_grid, geotile_grid and geohex_grid for the dimensions grouped by geo grid cells.
+	groupsType string
+	// options are the options of the aggregation, for the dimensions grouping the values by intervals or ranges.
+	options map[string]interface{}
+}
+
+// rangeGroupsTypes are the types of the aggregations which group the values of a dimension by ranges.
+var rangeGroupsTypes = map[string]bool{
+	"range":      true,
+	"date_range": true,
+	"ip_range":   true,
+}
+
+// geoGridGroupsTypes are the types of the aggregations which group the values of a geo_point dimension by the cells of a grid.
+var geoGridGroupsTypes = map[string]bool{
+	"geohash_grid": true,
+	"geotile_grid": true,
+	"geohex_grid":  true,
+}
+
+// compositeUnsupportedOptions are the options of the aggregations which are not supported by the sources of a composite aggregation.
+var compositeUnsupportedOptions = map[string][]string{
+	"date_histogram": {"min_doc_count", "extended_bounds"},
+	"histogram":      {"min_doc_count", "extended_bounds", "offset"},
+}
+
+// prepareGroupBy prepares the aggregation which groups the documents by the dimensions of the `group_by` collection argument.
+// In `composite` mode, all the groups are returned page by page, with a composite aggregation;
+// in `terms` mode, the top groups of each dimension are returned, with nested terms aggregations;
+// in `significant_terms` and `rare_terms` modes, the groups of the last dimension are its significant or rare terms.
+// The limit of the query is the number of groups (per dimension in `terms` mode), and the aggregates of the argument are computed for each group.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-composite-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-terms-aggregation.html
+func prepareGroupBy(ctx context.Context, request *schema.QueryRequest, state *types.State, index string) (map[string]interface{}, error) {
+	arg, ok := request.Arguments["group_by"]
+	if !ok {
+		return nil, nil
+	}
+	options, ok := removeNullOptions(arg.Value).(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	if request.Query.Offset != nil {
+		return nil, schema.UnprocessableContentError("offset is not supported with group_by, use the 'after' option to paginate the groups", nil)
+	}
+	if request.Query.OrderBy != nil && len(request.Query.OrderBy.Elements) != 0 {
+		return nil, schema.UnprocessableContentError("order_by is not supported with group_by, use the 'order_by' option to order the groups", nil)
+	}
+
+	dimensions, err := getGroupDimensions(options["dimensions"], state, index)
+	if err != nil {
+		return nil, err
+	}
+
+	size := ctx.Value(elasticsearch.DEFAULT_RESULT_SIZE_KEY).(int)
+	if request.Query.Limit != nil {
+		size = *request.Query.Limit
+	}
+
+	groupBy := &types.GroupBy{
+		Mode:            "composite",
+		Aggregates:      &types.PostProcessor{},
+		RangeDimensions: make(map[string]bool),
+		GridDimensions:  make(map[string]string),
+		DateDimensions:  make(map[string]bool),
+	}
+	for _, dimension := range dimensions {
+		groupBy.Dimensions = append(groupBy.Dimensions, dimension.column)
+		if dimension.fieldType == "date" || dimension.fieldType == "date_nanos" {
+			groupBy.DateDimensions[dimension.column] = true
+		}
+		if rangeGroupsTypes[dimension.groupsType] {
+			groupBy.RangeDimensions[dimension.column] = true
+		}
+		if geoGridGroupsTypes[dimension.groupsType] {
+			groupBy.GridDimensions[dimension.column] = dimension.groupsType
+		}
+	}
+	if mode, ok := options["mode"].(string); ok {
+		groupBy.Mode = mode
+	} else if len(request.Variables) != 0 {
+		// the groups of each variable set are grouped under a filters aggregation, which can't have a composite sub-aggregation
+		groupBy.Mode = "terms"
+	}
+	if groupBy.Mode == "composite" && len(request.Variables) != 0 {
+		return nil, schema.UnprocessableContentError("composite mode is not supported in queries with variables, use terms mode", nil)
+	}
+
+	aggregates, err := prepareGroupAggregates(ctx, options["aggregates"], groupBy.Aggregates, state, index)
+	if err != nil {
+		return nil, err
+	}
+	for _, dimension := range dimensions {
+		if geoGridGroupsTypes[dimension.groupsType] {
+			// the location of a group in a grid cell is the centroid of the locations of its documents
+			aggregates[cellCentroidAggregationPrefix+dimension.column] = map[string]interface{}{
+				"geo_centroid": map[string]interface{}{
+					"field": dimension.field,
+				},
+			}
+		}
+	}
+
+	var groups map[string]interface{}
+	switch groupBy.Mode {
+	case "composite":
+		groups, err = prepareCompositeGroups(dimensions, size, options["after"], aggregates)
+		if err != nil {
+			return nil, err
+		}
+	case "terms":
+		groups, err = prepareTermsGroups(dimensions, size, options["order_by"], aggregates)
+		if err != nil {
+			return nil, err
+		}
+	case "significant_terms", "rare_terms":
+		if err := prepareTermsAnalysisDimension(&dimensions[len(dimensions)-1], groupBy.Mode, options, state, index); err != nil {
+			return nil, err
+		}
+		groups, err = prepareTermsGroups(dimensions, size, options["order_by"], aggregates)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, schema.UnprocessableContentError("invalid group_by mode, expected composite, terms, significant_terms or rare_terms", map[string]any{
+			"value": groupBy.Mode,
+		})
+	}
+
+	postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+	postProcessor.GroupBy = groupBy
+	return groups, nil
+}
+
+// getGroupDimensions validates the dimensions of the `group_by` argument.
+func getGroupDimensions(value interface{}, state *types.State, index string) ([]groupDimension, error) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) == 0 {
+		return nil, schema.UnprocessableContentError("at least one dimension is required in group_by", nil)
+	}
+
+	dimensions := make([]groupDimension, 0, len(values))
+	for _, value := range values {
+		options, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, schema.UnprocessableContentError("invalid group_by dimension, expected an object", map[string]any{
+				"value": value,
+			})
+		}
+		column, _ := options["column"].(string)
+		dimension := groupDimension{
+			column:     column,
+			groupsType: "terms",
+		}
+		dimension.order, _ = options["order"].(string)
+		dimension.missingBucket, _ = options["missing_bucket"].(bool)
+
+		groupingOptions := 0
+		geoGrid := ""
+		for _, option := range []string{"date_histogram", "histogram", "ranges", "geohash_grid", "geotile_grid", "geohex_grid"} {
+			if _, ok := options[option]; ok {
+				groupingOptions++
+				if geoGridGroupsTypes[option] {
+					geoGrid = option
+				}
+			}
+		}
+		if groupingOptions > 1 {
+			return nil, schema.UnprocessableContentError("only one of date_histogram, histogram, ranges and the geo grids can be set in a group_by dimension", map[string]any{
+				"value": column,
+			})
+		}
+
+		var err error
+		if dateHistogram, ok := options["date_histogram"].(map[string]interface{}); ok {
+			dimension.field, dimension.fieldType, err = getGroupField(column, state, index)
+			if err != nil {
+				return nil, err
+			}
+			if dimension.fieldType != "date" && dimension.fieldType != "date_nanos" {
+				return nil, schema.UnprocessableContentError("date_histogram is only supported on date fields", map[string]any{
+					"value": column,
+				})
+			}
+			_, hasCalendarInterval := dateHistogram["calendar_interval"]
+			_, hasFixedInterval := dateHistogram["fixed_interval"]
+			if hasCalendarInterval == hasFixedInterval {
+				return nil, schema.UnprocessableContentError("either calendar_interval or fixed_interval is required in date_histogram", map[string]any{
+					"value": column,
+				})
+			}
+			dimension.groupsType = "date_histogram"
+			dimension.options = dateHistogram
+		} else if histogram, ok := options["histogram"].(map[string]interface{}); ok {
+			dimension.field, dimension.fieldType, err = getGroupNumericField(column, "histogram", state, index)
+			if err != nil {
+				return nil, err
+			}
+			if dimension.fieldType == "date" || dimension.fieldType == "date_nanos" || dimension.fieldType == "ip" {
+				return nil, schema.UnprocessableContentError("histogram is only supported on numeric fields, use date_histogram for date fields", map[string]any{
+					"value": column,
+				})
+			}
+			if _, ok := histogram["interval"]; !ok {
+				return nil, schema.UnprocessableContentError("interval is required in histogram", map[string]any{
+					"value": column,
+				})
+			}
+			dimension.groupsType = "histogram"
+			dimension.options = histogram
+		} else if ranges, ok := options["ranges"].([]interface{}); ok {
+			dimension.field, dimension.fieldType, err = getGroupNumericField(column, "range", state, index)
+			if err != nil {
+				return nil, err
+			}
+			if len(ranges) == 0 {
+				return nil, schema.UnprocessableContentError("at least one range is required in ranges", map[string]any{
+					"value": column,
+				})
+			}
+			switch dimension.fieldType {
+			case "date", "date_nanos":
+				dimension.groupsType = "date_range"
+			case "ip":
+				dimension.groupsType = "ip_range"
+			default:
+				dimension.groupsType = "range"
+			}
+			dimension.options = map[string]interface{}{
+				"ranges": ranges,
+			}
+		} else if geoGrid != "" {
+			dimension.fieldType, _, err = getGroupFieldProperties(column, state, index)
+			if err != nil {
+				return nil, err
+			}
+			if dimension.fieldType != "geo_point" {
+				return nil, schema.UnprocessableContentError(geoGrid+" is only supported on geo_point fields", map[string]any{
+					"value": column,
+				})
+			}
+			dimension.field = column
+			dimension.groupsType = geoGrid
+			dimension.options, _ = options[geoGrid].(map[string]interface{})
+		} else {
+			dimension.field, dimension.fieldType, err = getGroupField(column, state, index)
+			if err != nil {
+				return nil, err
+			}
+		}
+		dimensions = append(dimensions, dimension)
+	}
+	return dimensions, nil
+}
+
+// getGroupField returns the field to group a column by, and its type. The field must be a keyword, numeric or boolean field
+// out of a nested field. The keyword subfield of a text field is used to group by the text field.
+func getGroupField(column string, state *types.State, index string) (string, string, error) {
+	fieldType, subFieldMap, err := getGroupFieldProperties(column, state, index)
+	if err != nil {
+		return "", "", err
+	}
+	if internal.KeywordFamilyOfTypes[fieldType] || internal.NumericFamilyOfTypes[fieldType] || fieldType == "boolean" {
+		return column, fieldType, nil
+	}
+	if field, ok := internal.GetBestFieldOrSubFieldForFamily(column, fieldType, subFieldMap, internal.KeywordFamilyOfTypes); ok {
+		return field, "keyword", nil
+	}
+
+	return "", "", schema.UnprocessableContentError("grouping is only supported on keyword, numeric and boolean fields", map[string]any{
+		"value": column,
+	})
+}
+
+// getGroupNumericField returns the field to group a column by intervals or ranges, and its type. The field must be a numeric,
+// date or ip field out of a nested field. The numeric subfield of a field is used to group by the field, as for aggregations.
+func getGroupNumericField(column string, aggregation string, state *types.State, index string) (string, string, error) {
+	fieldType, subFieldMap, err := getGroupFieldProperties(column, state, index)
+	if err != nil {
+		return "", "", err
+	}
+	if fieldType == "ip" {
+		return column, fieldType, nil
+	}
+
+	field, _ := internal.GetBestFieldOrSubFieldForAggregation(column, fieldType, subFieldMap, aggregation)
+	if field != column {
+		// the subfield map holds the name of the subfield of each type
+		for subFieldType, subField := range subFieldMap {
+			if column+"."+subField == field {
+				fieldType = subFieldType
+			}
+		}
+	}
+	if !internal.NumericFamilyOfTypes[fieldType] {
+		return "", "", schema.UnprocessableContentError("grouping by intervals or ranges is only supported on numeric, date and ip fields", map[string]any{
+			"value": column,
+		})
+	}
+	return field, fieldType, nil
+}
+
+// getGroupFieldProperties returns the type and the subfields of a column to group by, which must not be a field of nested documents.
+func getGroupFieldProperties(column string, state *types.State, index string) (string, map[string]string, error) {
+	if column == "" {
+		return "", nil, schema.UnprocessableContentError("missing 'column' value in group_by dimension", nil)
+	}
+
+	splitColumn := strings.Split(column, ".")
+	_, nestedPath := joinFieldPath(state, splitColumn[1:], splitColumn[0], index)
+	if nestedPath != "" {
+		return "", nil, schema.UnprocessableContentError("grouping is not supported on nested fields", map[string]any{
+			"value": column,
+		})
+	}
+
+	fieldType, subFieldMap, _, err := state.Configuration.GetFieldProperties(index, column)
+	if err != nil {
+		return "", nil, schema.UnprocessableContentError("unable to get field types", map[string]any{
+			"value": column,
+		})
+	}
+	return fieldType, subFieldMap, nil
+}
+
+// prepareGroupAggregates prepares the aggregations computed for each group, from the aggregates of the `group_by` argument.
+// The aggregates are translated into NDC aggregates, so that they are prepared and extracted like the aggregates of the query,
+// with the given post processor.
+func prepareGroupAggregates(ctx context.Context, value interface{}, postProcessor *types.PostProcessor, state *types.State, index string) (map[string]interface{}, error) {
+	values, _ := value.([]interface{})
+	aggregates := make(schema.QueryAggregates, len(values))
+	predicates := make(map[string]schema.Expression)
+	for _, value := range values {
+		options, _ := value.(map[string]interface{})
+		name, _ := options["name"].(string)
+		function, _ := options["function"].(string)
+		if name == "" || function == "" {
+			return nil, schema.UnprocessableContentError("'name' and 'function' are required in group_by aggregates", map[string]any{
+				"value": value,
+			})
+		}
+
+		column, _ := options["column"].(string)
+		var fieldPath []string
+		if splitColumn := strings.Split(column, "."); len(splitColumn) > 1 {
+			column, fieldPath = splitColumn[0], splitColumn[1:]
+		}
+
+		switch {
+		case function == "count" && column == "":
+			aggregates[name] = schema.NewAggregateStarCount().Encode()
+		case function == "count":
+			distinct, _ := options["distinct"].(bool)
+			aggregates[name] = schema.NewAggregateColumnCount(column, distinct, fieldPath).Encode()
+		case column == "":
+			return nil, schema.UnprocessableContentError("'column' is required in group_by aggregates, except for count", map[string]any{
+				"value": name,
+			})
+		default:
+			aggregates[name] = schema.NewAggregateSingleColumn(column, function, fieldPath).Encode()
+		}
+
+		if predicate, ok := options["predicate"]; ok {
+			expression, err := decodePredicate(name, predicate)
+			if err != nil {
+				return nil, err
+			}
+			predicates[name] = expression
+		}
+	}
+
+	// the distinct values of each group can't be counted exactly, since the composite aggregation can't be a sub-aggregation
+	distinctCount, isExact := getDistinctCountContext(ctx), false
+	distinctCount.Exact = &isExact
+	ctx = context.WithValue(ctx, "distinctCount", distinctCount)
+
+	return prepareAggregateQuery(context.WithValue(ctx, "postProcessor", postProcessor), aggregates, predicates, state, index)
+}
+
+// prepareTermsAnalysisDimension sets the aggregation of the last dimension in the `significant_terms` and `rare_terms` modes,
+// which must be a keyword column grouped by value. The foreground set of the significant terms is the documents matching the predicate
+// of the query (in the group of the previous dimensions), and the background set is the documents matching the background predicate,
+// or the whole index.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-significantterms-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-rare-terms-aggregation.html
+func prepareTermsAnalysisDimension(dimension *groupDimension, mode string, options map[string]interface{}, state *types.State, index string) error {
+	if dimension.groupsType != "terms" || !internal.KeywordFamilyOfTypes[dimension.fieldType] {
+		return schema.UnprocessableContentError(mode+" mode is only supported when the last dimension is a keyword column grouped by value", map[string]any{
+			"value": dimension.column,
+		})
+	}
+	dimension.groupsType = mode
+	dimension.options = make(map[string]interface{})
+
+	if mode == "rare_terms" {
+		if _, ok := options["background_predicate"]; ok {
+			return schema.UnprocessableContentError("background_predicate is only supported in significant_terms mode", nil)
+		}
+		if maxDocCount, ok := options["max_doc_count"]; ok {
+			dimension.options["max_doc_count"] = maxDocCount
+		}
+		return nil
+	}
+
+	if _, ok := options["max_doc_count"]; ok {
+		return schema.UnprocessableContentError("max_doc_count is only supported in rare_terms mode", nil)
+	}
+	value, ok := options["background_predicate"]
+	if !ok {
+		return nil
+	}
+	predicate, err := decodePredicate("background_predicate", value)
+	if err != nil {
+		return err
+	}
+	filter, err := prepareFilterQuery(predicate, state, index)
+	if err != nil {
+		return err
+	}
+	if err := checkAggregationFilterVariables(filter, "background_predicate", nil); err != nil {
+		return err
+	}
+	dimension.options["background_filter"] = filter
+	return nil
+}
+
+// prepareCompositeGroups prepares a composite aggregation with a source for each dimension, named after its column:
+// a date_histogram or histogram source for the dimensions grouped by intervals, a geotile_grid source for the dimensions grouped by map tiles,
+// and a terms source for the other dimensions.
+// The dimensions grouped by ranges, geohashes and H3 cells are not supported by composite aggregations.
+func prepareCompositeGroups(dimensions []groupDimension, size int, after interface{}, aggregates map[string]interface{}) (map[string]interface{}, error) {
+	sources := make([]interface{}, 0, len(dimensions))
+	for _, dimension := range dimensions {
+		source, err := prepareDimensionGroups(dimension, true)
+		if err != nil {
+			return nil, err
+		}
+		if dimension.order != "" {
+			source[dimension.groupsType].(map[string]interface{})["order"] = dimension.order
+		}
+		if dimension.missingBucket {
+			source[dimension.groupsType].(map[string]interface{})["missing_bucket"] = true
+		}
+		sources = append(sources, map[string]interface{}{
+			dimension.column: source,
+		})
+	}
+
+	composite := map[string]interface{}{
+		"size":    size,
+		"sources": sources,
+	}
+	if after != nil {
+		composite["after"] = after
+	}
+
+	groups := map[string]interface{}{
+		"composite": composite,
+	}
+	if len(aggregates) != 0 {
+		groups["aggs"] = aggregates
+	}
+	return groups, nil
+}
+
+// prepareTermsGroups prepares a bucket aggregation for each dimension, nested in the aggregation of the previous dimension:
+// a date_histogram or histogram aggregation for the dimensions grouped by intervals, a range aggregation for the dimensions
+// grouped by ranges, a geo grid aggregation for the dimensions grouped by geo grid cells, and a terms aggregation for the other dimensions,
+// except for the last dimension in the `significant_terms` and `rare_terms` modes.
+// The groups of the last dimension are ordered by the given order, and the groups of the other dimensions by count or key only,
+// as they can't be ordered by the aggregates of the groups of the last dimension.
+func prepareTermsGroups(dimensions []groupDimension, size int, orderBy interface{}, aggregates map[string]interface{}) (map[string]interface{}, error) {
+	order, err := getGroupOrder(orderBy, aggregates)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups map[string]interface{}
+	for i := len(dimensions) - 1; i >= 0; i-- {
+		group, err := prepareDimensionGroups(dimensions[i], false)
+		if err != nil {
+			return nil, err
+		}
+		terms := group[dimensions[i].groupsType].(map[string]interface{})
+		groupsType := dimensions[i].groupsType
+		if groupsType == "terms" || groupsType == "significant_terms" || geoGridGroupsTypes[groupsType] {
+			terms["size"] = size
+		}
+		// the buckets of ranges are in the order of the ranges, the cells of geo grids by descending document count,
+		// the significant terms by descending score and the rare terms by ascending document count
+		isUnordered := rangeGroupsTypes[groupsType] || geoGridGroupsTypes[groupsType] || groupsType == "significant_terms" || groupsType == "rare_terms"
+
+		if groups == nil {
+			if len(order) != 0 && isUnordered {
+				return nil, schema.UnprocessableContentError("order_by is not supported when the last dimension is grouped by ranges, geo grid cells, significant or rare terms", map[string]any{
+					"value": dimensions[i].column,
+				})
+			}
+			if len(order) != 0 {
+				terms["order"] = order
+			}
+			if len(aggregates) != 0 {
+				group["aggs"] = aggregates
+			}
+		} else {
+			if bucketOrder := getBucketOrder(order); len(bucketOrder) != 0 && !isUnordered {
+				terms["order"] = bucketOrder
+			}
+			group["aggs"] = map[string]interface{}{
+				groupsAggregationName: groups,
+			}
+		}
+		groups = group
+	}
+	return groups, nil
+}
+
+// prepareDimensionGroups prepares the aggregation (or composite source) which groups the values of a dimension.
+// The keys of the intervals of a date_histogram, and the bounds of date ranges, are formatted as ISO 8601 dates, like the values of the date columns.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-datehistogram-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-histogram-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-range-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-geotilegrid-aggregation.html
+func prepareDimensionGroups(dimension groupDimension, isCompositeSource bool) (map[string]interface{}, error) {
+	if isCompositeSource {
+		if rangeGroupsTypes[dimension.groupsType] {
+			return nil, schema.UnprocessableContentError("ranges are only supported in terms mode", map[string]any{
+				"value": dimension.column,
+			})
+		}
+		if dimension.groupsType == "geohash_grid" || dimension.groupsType == "geohex_grid" {
+			return nil, schema.UnprocessableContentError(dimension.groupsType+" is only supported in terms mode, use geotile_grid in composite mode", map[string]any{
+				"value": dimension.column,
+			})
+		}
+		for _, option := range compositeUnsupportedOptions[dimension.groupsType] {
+			if _, ok := dimension.options[option]; ok {
+				return nil, schema.UnprocessableContentError(option+" of "+dimension.groupsType+" is only supported in terms mode", map[string]any{
+					"value": dimension.column,
+				})
+			}
+		}
+	}
+
+	options := maps.Clone(dimension.options)
+	if options == nil {
+		options = make(map[string]interface{})
+	}
+	options["field"] = dimension.field
+	if dimension.groupsType == "date_histogram" || dimension.groupsType == "date_range" {
+		options["format"] = "strict_date_optional_time"
+		if dimension.fieldType == "date_nanos" {
+			options["format"] = "strict_date_optional_time_nanos"
+		}
+	}
+
+	return map[string]interface{}{
+		dimension.groupsType: options,
+	}, nil
+}
+
+// getGroupOrder returns the order of terms groups from the `order_by` option of the `group_by` argument.
+func getGroupOrder(value interface{}, aggregates map[string]interface{}) ([]interface{}, error) {
+	values, _ := value.([]interface{})
+	order := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		options, _ := value.(map[string]interface{})
+		by, _ := options["by"].(string)
+		if _, ok := aggregates[by]; !ok && by != "_count" && by != "_key" {
+			return nil, schema.UnprocessableContentError("groups can only be ordered by _count, _key or an aggregate of the groups", map[string]any{
+				"value": by,
+			})
+		}
+		direction, ok := options["direction"].(string)
+		if !ok {
+			direction = "desc"
+		}
+		order = append(order, map[string]interface{}{
+			by: direction,
+		})
+	}
+	return order, nil
+}
+
+// getBucketOrder returns the orders by `_count` or `_key` of the given order.
+func getBucketOrder(order []interface{}) []interface{} {
+	bucketOrder := make([]interface{}, 0, len(order))
+	for _, value := range order {
+		for by := range value.(map[string]interface{}) {
+			if by == "_count" || by == "_key" {
+				bucketOrder = append(bucketOrder, value)
+			}
+		}
+	}
+	return bucketOrder
+}
+
+// extractGroups returns the rows of the groups of a query with the `group_by` argument.
+// The values of the dimensions are set in their columns, and the key, document count and aggregates of the group in the `_group` column.
+func extractGroups(aggregations map[string]interface{}, postProcessor *types.PostProcessor) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0)
+	groups, ok := aggregations[groupsAggregationName].(map[string]interface{})
+	if !ok {
+		return rows
+	}
+
+	groupBy := postProcessor.GroupBy
+	if groupBy.Mode != "composite" {
+		collectTermsGroups(groups, groupBy, make(map[string]interface{}), nil, func(key map[string]interface{}, ranges []interface{}, bucket map[string]interface{}) {
+			rows = append(rows, extractGroup(key, ranges, bucket, postProcessor))
+		})
+		return rows
+	}
+
+	buckets, _ := groups["buckets"].([]interface{})
+	for _, bucket := range buckets {
+		bucket, ok := bucket.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key, _ := bucket["key"].(map[string]interface{})
+		rows = append(rows, extractGroup(key, nil, bucket, postProcessor))
+	}
+	return rows
+}
+
+// collectTermsGroups walks the buckets of the nested bucket aggregations, and calls collect with the key of each bucket of the last dimension,
+// and the bounds of the ranges of its dimensions grouped by ranges.
+func collectTermsGroups(groups map[string]interface{}, groupBy *types.GroupBy, key map[string]interface{}, ranges []interface{}, collect func(map[string]interface{}, []interface{}, map[string]interface{})) {
+	dimension := groupBy.Dimensions[len(key)]
+	buckets, _ := groups["buckets"].([]interface{})
+	for _, bucket := range buckets {
+		bucket, ok := bucket.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		bucketKey := make(map[string]interface{}, len(key)+1)
+		for column, value := range key {
+			bucketKey[column] = value
+		}
+		if keyAsString, ok := bucket["key_as_string"]; ok && groupBy.DateDimensions[dimension] {
+			// the keys of dates are returned as epoch milliseconds, and formatted in key_as_string
+			bucketKey[dimension] = keyAsString
+		} else {
+			bucketKey[dimension] = bucket["key"]
+		}
+
+		bucketRanges := ranges
+		if groupBy.RangeDimensions[dimension] {
+			bucketRanges = append(slices.Clone(ranges), extractGroupRange(dimension, bucket))
+		}
+
+		if len(bucketKey) == len(groupBy.Dimensions) {
+			collect(bucketKey, bucketRanges, bucket)
+		} else if subGroups, ok := bucket[groupsAggregationName].(map[string]interface{}); ok {
+			collectTermsGroups(subGroups, groupBy, bucketKey, bucketRanges, collect)
+		}
+	}
+}
+
+// extractGroupRange returns the bounds of the range of a bucket of a range aggregation.
+// The bounds of ip ranges are strings, and the bounds of date ranges are epoch milliseconds, formatted in from_as_string and to_as_string.
+func extractGroupRange(dimension string, bucket map[string]interface{}) map[string]interface{} {
+	groupRange := map[string]interface{}{
+		"column": dimension,
+		"key":    bucket["key"],
+	}
+	for _, bound := range []string{"from", "to"} {
+		value, ok := bucket[bound]
+		if !ok {
+			continue
+		}
+		if value, ok := value.(string); ok {
+			groupRange[bound+"_as_string"] = value
+			continue
+		}
+		groupRange[bound] = value
+		if valueAsString, ok := bucket[bound+"_as_string"]; ok {
+			groupRange[bound+"_as_string"] = valueAsString
+		}
+	}
+	return groupRange
+}
+
+// extractGroup returns the row of a group.
+// The columns of the dimensions grouped by ranges are not set, as the keys of the ranges are not values of the columns,
+// and the columns of the dimensions grouped by geo grid cells are set to the centroid of the locations of the group, as a GeoJSON Point.
+func extractGroup(key map[string]interface{}, ranges []interface{}, bucket map[string]interface{}, postProcessor *types.PostProcessor) map[string]interface{} {
+	groupBy := postProcessor.GroupBy
+
+	// the values of the dimensions are set like fetched fields, to create the objects of the columns of objects
+	source := make(map[string]interface{})
+	dimensionValues := make(map[string]interface{}, len(groupBy.Dimensions))
+	cells := make([]interface{}, 0, len(groupBy.GridDimensions))
+	for _, dimension := range groupBy.Dimensions {
+		if grid, ok := groupBy.GridDimensions[dimension]; ok {
+			centroid, _ := bucket[cellCentroidAggregationPrefix+dimension].(map[string]interface{})
+			cell := map[string]interface{}{
+				"column":   dimension,
+				"grid":     grid,
+				"key":      key[dimension],
+				"bounds":   getGeoGridCellBounds(grid, key[dimension]),
+				"centroid": extractGeoCentroid(centroid),
+			}
+			cells = append(cells, cell)
+			dimensionValues[dimension] = []interface{}{cell["centroid"]}
+		} else if !groupBy.RangeDimensions[dimension] {
+			dimensionValues[dimension] = []interface{}{key[dimension]}
+		}
+	}
+	extractFetchedFields(source, dimensionValues, groupBy.Dimensions)
+
+	aggregates := extractAggregates(schema.RowSetAggregates{}, bucket, groupBy.Aggregates)
+	if groupBy.Aggregates.StarAggregates != "" {
+		aggregates[groupBy.Aggregates.StarAggregates] = bucket["doc_count"]
+	}
+	group := map[string]interface{}{
+		"key":        key,
+		"doc_count":  bucket["doc_count"],
+		"aggregates": aggregates,
+	}
+	if len(groupBy.RangeDimensions) != 0 {
+		group["ranges"] = ranges
+	}
+	if len(groupBy.GridDimensions) != 0 {
+		group["cells"] = cells
+	}
+	if groupBy.Mode == "significant_terms" || groupBy.Mode == "rare_terms" {
+		// the rare terms have no score and background count
+		group["score"] = bucket["score"]
+		group["bg_count"] = bucket["bg_count"]
+	}
+	// like the other object columns, the group is returned as an array of one object
+	source["_group"] = []interface{}{group}
+
+	return extractDocument(source, postProcessor.SelectedFields)
+}
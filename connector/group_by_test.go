@@ -0,0 +1,325 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractGroups(t *testing.T) {
+	selectedFields := map[string]types.Field{
+		"customer_id": {Name: "customer_id"},
+		"transaction_details": {
+			Name: "transaction_details",
+			Fields: map[string]types.Field{
+				"currency": {Name: "currency"},
+			},
+		},
+		"_group": {Name: "_group"},
+	}
+
+	tests := []struct {
+		name            string
+		mode            string
+		dimensions      []string
+		rangeDimensions map[string]bool
+		gridDimensions  map[string]string
+		dateDimensions  map[string]bool
+		selectedFields  map[string]types.Field
+		aggregations    string
+		want            string
+	}{
+		{
+			name:           "composite",
+			mode:           "composite",
+			dimensions:     []string{"customer_id", "transaction_details.currency"},
+			selectedFields: selectedFields,
+			aggregations: `{
+  "_groups": {
+    "after_key": {"customer_id": "cust002", "transaction_details.currency": null},
+    "buckets": [
+      {"key": {"customer_id": "cust001", "transaction_details.currency": "USD"}, "doc_count": 3, "total_price": {"value": 42.5}},
+      {"key": {"customer_id": "cust002", "transaction_details.currency": null}, "doc_count": 1, "total_price": {"value": 0}}
+    ]
+  }
+}`,
+			want: `[
+  {
+    "customer_id": "cust001",
+    "transaction_details": [{"currency": "USD"}],
+    "_group": [{"key": {"customer_id": "cust001", "transaction_details.currency": "USD"}, "doc_count": 3, "aggregates": {"count": 3, "total_price": 42.5}}]
+  },
+  {
+    "customer_id": "cust002",
+    "transaction_details": [{"currency": null}],
+    "_group": [{"key": {"customer_id": "cust002", "transaction_details.currency": null}, "doc_count": 1, "aggregates": {"count": 1, "total_price": 0}}]
+  }
+]`,
+		},
+		{
+			name:           "terms",
+			mode:           "terms",
+			dimensions:     []string{"customer_id", "transaction_details.currency"},
+			selectedFields: selectedFields,
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {
+        "key": "cust001",
+        "doc_count": 3,
+        "_groups": {"buckets": [
+          {"key": "EUR", "doc_count": 2, "total_price": {"value": 20}},
+          {"key": "USD", "doc_count": 1, "total_price": {"value": 22.5}}
+        ]}
+      },
+      {
+        "key": "cust002",
+        "doc_count": 1,
+        "_groups": {"buckets": [{"key": "USD", "doc_count": 1, "total_price": {"value": 0}}]}
+      }
+    ]
+  }
+}`,
+			want: `[
+  {
+    "customer_id": "cust001",
+    "transaction_details": [{"currency": "EUR"}],
+    "_group": [{"key": {"customer_id": "cust001", "transaction_details.currency": "EUR"}, "doc_count": 2, "aggregates": {"count": 2, "total_price": 20}}]
+  },
+  {
+    "customer_id": "cust001",
+    "transaction_details": [{"currency": "USD"}],
+    "_group": [{"key": {"customer_id": "cust001", "transaction_details.currency": "USD"}, "doc_count": 1, "aggregates": {"count": 1, "total_price": 22.5}}]
+  },
+  {
+    "customer_id": "cust002",
+    "transaction_details": [{"currency": "USD"}],
+    "_group": [{"key": {"customer_id": "cust002", "transaction_details.currency": "USD"}, "doc_count": 1, "aggregates": {"count": 1, "total_price": 0}}]
+  }
+]`,
+		},
+		{
+			name:           "terms_with_date_histogram",
+			mode:           "terms",
+			dimensions:     []string{"timestamp", "customer_id"},
+			dateDimensions: map[string]bool{"timestamp": true},
+			selectedFields: map[string]types.Field{
+				"timestamp":   {Name: "timestamp"},
+				"customer_id": {Name: "customer_id"},
+				"_group":      {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {
+        "key_as_string": "2024-01-01T00:00:00.000Z",
+        "key": 1704067200000,
+        "doc_count": 1,
+        "_groups": {"buckets": [{"key": "cust001", "doc_count": 1, "total_price": {"value": 10}}]}
+      }
+    ]
+  }
+}`,
+			want: `[
+  {
+    "timestamp": "2024-01-01T00:00:00.000Z",
+    "customer_id": "cust001",
+    "_group": [{"key": {"timestamp": "2024-01-01T00:00:00.000Z", "customer_id": "cust001"}, "doc_count": 1, "aggregates": {"count": 1, "total_price": 10}}]
+  }
+]`,
+		},
+		{
+			name:       "terms_with_boolean",
+			mode:       "terms",
+			dimensions: []string{"is_refunded"},
+			selectedFields: map[string]types.Field{
+				"is_refunded": {Name: "is_refunded"},
+				"_group":      {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {"key": 1, "key_as_string": "true", "doc_count": 2, "total_price": {"value": 15}}
+    ]
+  }
+}`,
+			want: `[
+  {
+    "is_refunded": 1,
+    "_group": [{"key": {"is_refunded": 1}, "doc_count": 2, "aggregates": {"count": 2, "total_price": 15}}]
+  }
+]`,
+		},
+		{
+			name:            "terms_with_ranges",
+			mode:            "terms",
+			dimensions:      []string{"timestamp", "transaction_details.price"},
+			rangeDimensions: map[string]bool{"timestamp": true, "transaction_details.price": true},
+			selectedFields: map[string]types.Field{
+				"timestamp": {Name: "timestamp"},
+				"_group":    {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {
+        "key": "since_2024",
+        "from": 1704067200000,
+        "from_as_string": "2024-01-01T00:00:00.000Z",
+        "doc_count": 2,
+        "_groups": {"buckets": [
+          {"key": "*-100.0", "to": 100.0, "doc_count": 1, "total_price": {"value": 50}},
+          {"key": "100.0-*", "from": 100.0, "doc_count": 1, "total_price": {"value": 150}}
+        ]}
+      }
+    ]
+  }
+}`,
+			want: `[
+  {
+    "timestamp": null,
+    "_group": [{
+      "key": {"timestamp": "since_2024", "transaction_details.price": "*-100.0"},
+      "doc_count": 1,
+      "aggregates": {"count": 1, "total_price": 50},
+      "ranges": [
+        {"column": "timestamp", "key": "since_2024", "from": 1704067200000, "from_as_string": "2024-01-01T00:00:00.000Z"},
+        {"column": "transaction_details.price", "key": "*-100.0", "to": 100.0}
+      ]
+    }]
+  },
+  {
+    "timestamp": null,
+    "_group": [{
+      "key": {"timestamp": "since_2024", "transaction_details.price": "100.0-*"},
+      "doc_count": 1,
+      "aggregates": {"count": 1, "total_price": 150},
+      "ranges": [
+        {"column": "timestamp", "key": "since_2024", "from": 1704067200000, "from_as_string": "2024-01-01T00:00:00.000Z"},
+        {"column": "transaction_details.price", "key": "100.0-*", "from": 100.0}
+      ]
+    }]
+  }
+]`,
+		},
+		{
+			name:           "composite_with_geotile_grid",
+			mode:           "composite",
+			dimensions:     []string{"location"},
+			gridDimensions: map[string]string{"location": "geotile_grid"},
+			selectedFields: map[string]types.Field{
+				"location": {Name: "location"},
+				"_group":   {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {
+        "key": {"location": "1/1/0"},
+        "doc_count": 2,
+        "_centroid.location": {"location": {"lat": 48.85, "lon": 2.35}, "count": 2},
+        "total_price": {"value": 30}
+      }
+    ]
+  }
+}`,
+			want: `[
+  {
+    "location": {"type": "Point", "coordinates": [2.35, 48.85]},
+    "_group": [{
+      "key": {"location": "1/1/0"},
+      "doc_count": 2,
+      "aggregates": {"count": 2, "total_price": 30},
+      "cells": [
+        {
+          "column": "location",
+          "grid": "geotile_grid",
+          "key": "1/1/0",
+          "bounds": {
+            "type": "Polygon",
+            "bbox": [0, 0, 180, 85.05112877980659],
+            "coordinates": [[[0, 0], [180, 0], [180, 85.05112877980659], [0, 85.05112877980659], [0, 0]]]
+          },
+          "centroid": {"type": "Point", "coordinates": [2.35, 48.85]}
+        }
+      ]
+    }]
+  }
+]`,
+		},
+		{
+			name:       "significant_terms",
+			mode:       "significant_terms",
+			dimensions: []string{"customer_id"},
+			selectedFields: map[string]types.Field{
+				"customer_id": {Name: "customer_id"},
+				"_group":      {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "doc_count": 12,
+    "bg_count": 1200,
+    "buckets": [
+      {"key": "cust042", "doc_count": 5, "score": 0.42, "bg_count": 20, "total_price": {"value": 310}}
+    ]
+  }
+}`,
+			want: `[
+  {
+    "customer_id": "cust042",
+    "_group": [{"key": {"customer_id": "cust042"}, "doc_count": 5, "score": 0.42, "bg_count": 20, "aggregates": {"count": 5, "total_price": 310}}]
+  }
+]`,
+		},
+		{
+			name:       "rare_terms",
+			mode:       "rare_terms",
+			dimensions: []string{"customer_id"},
+			selectedFields: map[string]types.Field{
+				"customer_id": {Name: "customer_id"},
+				"_group":      {Name: "_group"},
+			},
+			aggregations: `{
+  "_groups": {
+    "buckets": [
+      {"key": "cust007", "doc_count": 1, "total_price": {"value": 12}}
+    ]
+  }
+}`,
+			want: `[
+  {
+    "customer_id": "cust007",
+    "_group": [{"key": {"customer_id": "cust007"}, "doc_count": 1, "score": null, "bg_count": null, "aggregates": {"count": 1, "total_price": 12}}]
+  }
+]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postProcessor := &types.PostProcessor{
+				SelectedFields: tt.selectedFields,
+				GroupBy: &types.GroupBy{
+					Mode:            tt.mode,
+					Dimensions:      tt.dimensions,
+					RangeDimensions: tt.rangeDimensions,
+					GridDimensions:  tt.gridDimensions,
+					DateDimensions:  tt.dateDimensions,
+					Aggregates: &types.PostProcessor{
+						StarAggregates:  "count",
+						ColumnAggregate: map[string]bool{"total_price": false},
+					},
+				},
+			}
+
+			var aggregations map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.aggregations), &aggregations))
+
+			got, err := json.Marshal(extractGroups(aggregations, postProcessor))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
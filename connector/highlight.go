@@ -0,0 +1,164 @@
+package connector
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// prepareHighlight prepares the highlight option of the search from the `highlight` collection argument.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/highlighting.html
+func prepareHighlight(arguments map[string]schema.Argument) map[string]interface{} {
+	arg, ok := arguments["highlight"]
+	if !ok {
+		return nil
+	}
+	options, ok := removeNullOptions(arg.Value).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	highlightFields := make(map[string]interface{})
+	if fields, ok := options["fields"].([]interface{}); ok {
+		for _, field := range fields {
+			if fieldName, ok := field.(string); ok {
+				highlightFields[fieldName] = map[string]interface{}{}
+			}
+		}
+	}
+	options["fields"] = highlightFields
+
+	return options
+}
+
+// addNestedHighlight adds inner hits to the nested queries of the query, with the highlight of the highlighted fields under their path.
+// Nested documents are not highlighted by the highlight of the search, they are only highlighted in the inner hits of the nested query which matched them.
+// Inner hits are only added to the first nested query of a path, as inner hits names must be unique.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/inner-hits.html#nested-inner-hits
+func addNestedHighlight(query interface{}, highlight map[string]interface{}, highlightedPaths map[string]bool) {
+	switch q := query.(type) {
+	case map[string]interface{}:
+		if nestedQuery, ok := q["nested"].(map[string]interface{}); ok {
+			if path, ok := nestedQuery["path"].(string); ok && !highlightedPaths[path] {
+				if nestedHighlight := getNestedHighlight(highlight, path); nestedHighlight != nil {
+					nestedQuery["inner_hits"] = map[string]interface{}{
+						"_source":   false,
+						"highlight": nestedHighlight,
+					}
+					highlightedPaths[path] = true
+				}
+			}
+		}
+		for _, value := range q {
+			addNestedHighlight(value, highlight, highlightedPaths)
+		}
+	case []interface{}:
+		for _, value := range q {
+			addNestedHighlight(value, highlight, highlightedPaths)
+		}
+	case []map[string]interface{}:
+		for _, value := range q {
+			addNestedHighlight(value, highlight, highlightedPaths)
+		}
+	}
+}
+
+// getNestedHighlight returns the highlight options restricted to the highlighted fields under the given nested path,
+// or nil if no highlighted field is under it.
+func getNestedHighlight(highlight map[string]interface{}, path string) map[string]interface{} {
+	nestedFields := make(map[string]interface{})
+	for fieldName, fieldOptions := range highlight["fields"].(map[string]interface{}) {
+		if strings.HasPrefix(fieldName, path+".") {
+			nestedFields[fieldName] = fieldOptions
+		}
+	}
+	if len(nestedFields) == 0 {
+		return nil
+	}
+
+	nestedHighlight := make(map[string]interface{}, len(highlight))
+	for key, value := range highlight {
+		nestedHighlight[key] = value
+	}
+	nestedHighlight["fields"] = nestedFields
+	return nestedHighlight
+}
+
+// extractHighlight returns the `_highlight` object of a hit, which mirrors the highlighted fields:
+// the fragments of `address.city` are returned under `address`, in `city`.
+// The fragments of nested documents are read from the inner hits of the hit.
+func extractHighlight(hit map[string]interface{}) map[string]interface{} {
+	highlight := make(map[string]interface{})
+	addHitHighlight(highlight, hit)
+	return highlight
+}
+
+// addHitHighlight adds the highlighted fragments of a hit, and of its inner hits, to the highlight object.
+func addHitHighlight(highlight map[string]interface{}, hit map[string]interface{}) {
+	if hitHighlight, ok := hit["highlight"].(map[string]interface{}); ok {
+		// a field is added before its subfields, which are then skipped
+		fieldPaths := make([]string, 0, len(hitHighlight))
+		for fieldPath := range hitHighlight {
+			fieldPaths = append(fieldPaths, fieldPath)
+		}
+		sort.Strings(fieldPaths)
+		for _, fieldPath := range fieldPaths {
+			fragments, ok := hitHighlight[fieldPath].([]interface{})
+			if !ok {
+				continue
+			}
+			addHighlightFragments(highlight, strings.Split(fieldPath, "."), fragments)
+		}
+	}
+
+	innerHits, ok := hit["inner_hits"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, innerHit := range innerHits {
+		innerHitData, ok := innerHit.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		innerHitHits, ok := innerHitData["hits"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hits, ok := innerHitHits["hits"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, nestedHit := range hits {
+			if nestedHit, ok := nestedHit.(map[string]interface{}); ok {
+				addHitHighlight(highlight, nestedHit)
+			}
+		}
+	}
+}
+
+// addHighlightFragments adds the fragments of a field to the highlight object, under the objects of its path.
+// The fragments of the same field in several nested documents are appended.
+func addHighlightFragments(highlight map[string]interface{}, fieldPath []string, fragments []interface{}) {
+	object := highlight
+	for _, objectName := range fieldPath[:len(fieldPath)-1] {
+		subObject, ok := object[objectName].(map[string]interface{})
+		if !ok {
+			if _, exists := object[objectName]; exists {
+				// a subfield (e.g. `name.keyword`) of a highlighted field can't be added under its fragments
+				return
+			}
+			subObject = make(map[string]interface{})
+			object[objectName] = subObject
+		}
+		object = subObject
+	}
+
+	fieldName := fieldPath[len(fieldPath)-1]
+	if existingFragments, ok := object[fieldName].([]interface{}); ok {
+		fragments = append(existingFragments, fragments...)
+	} else if _, exists := object[fieldName]; exists {
+		return
+	}
+	object[fieldName] = fragments
+}
@@ -0,0 +1,42 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractHighlight(t *testing.T) {
+	hit := `{
+  "_id": "1",
+  "highlight": {
+    "guest_name": ["John <em>Smith</em>"],
+    "guest_name.keyword": ["<em>john smith</em>"],
+    "address.city": ["<em>Paris</em>"]
+  },
+  "inner_hits": {
+    "rooms": {
+      "hits": {
+        "hits": [
+          {"highlight": {"rooms.number": ["<em>101</em>"]}},
+          {"highlight": {"rooms.number": ["<em>102</em>"]}},
+          {}
+        ]
+      }
+    }
+  }
+}`
+	want := `{
+  "guest_name": ["John <em>Smith</em>"],
+  "address": {"city": ["<em>Paris</em>"]},
+  "rooms": {"number": ["<em>101</em>", "<em>102</em>"]}
+}`
+
+	var hitData map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(hit), &hitData))
+
+	got, err := json.Marshal(extractHighlight(hitData))
+	assert.NoError(t, err)
+	assert.JSONEq(t, want, string(got))
+}
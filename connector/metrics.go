@@ -0,0 +1,87 @@
+package connector
+
+import (
+	"strings"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// prepareTopMetricsOptions prepares the options of a `top_metrics` aggregation, which returns the value of the field
+// in the first document of the index, sorted by the configured sort of the index.
+// On a field of nested documents (at the given path), the sort field must be a field of the same nested documents.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-top-metrics.html
+func prepareTopMetricsOptions(field string, path string, index string, settings *types.AggregationSettings) (map[string]interface{}, error) {
+	sort, ok := settings.GetTopMetricsSort(index)
+	if !ok {
+		return nil, schema.UnprocessableContentError("top_metrics requires a 'top_metrics_sort' aggregation setting for the index in the configuration", map[string]any{
+			"index": index,
+		})
+	}
+	if !isFieldOfNestedPath(sort.Field, path) {
+		return nil, schema.UnprocessableContentError("top_metrics on a nested field requires a 'top_metrics_sort' field of the same nested documents", map[string]any{
+			"field": field,
+			"sort":  sort.Field,
+		})
+	}
+
+	return map[string]interface{}{
+		"metrics": map[string]interface{}{
+			"field": field,
+		},
+		"sort": map[string]interface{}{
+			sort.Field: sort.GetOrder(),
+		},
+		"size": 1,
+	}, nil
+}
+
+// prepareWeightedAvgOptions prepares the options of a `weighted_avg` aggregation, which averages the values of the field
+// weighted by the values of the configured weight field of the index.
+// On a field of nested documents (at the given path), the weight field must be a field of the same nested documents.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-weight-avg-aggregation.html
+func prepareWeightedAvgOptions(field string, path string, index string, settings *types.AggregationSettings) (map[string]interface{}, error) {
+	weightField, ok := settings.GetWeightField(index)
+	if !ok {
+		return nil, schema.UnprocessableContentError("weighted_avg requires a 'weight_fields' aggregation setting for the index in the configuration", map[string]any{
+			"index": index,
+		})
+	}
+	if !isFieldOfNestedPath(weightField, path) {
+		return nil, schema.UnprocessableContentError("weighted_avg on a nested field requires a 'weight_fields' field of the same nested documents", map[string]any{
+			"field":  field,
+			"weight": weightField,
+		})
+	}
+
+	return map[string]interface{}{
+		"value": map[string]interface{}{
+			"field": field,
+		},
+		"weight": map[string]interface{}{
+			"field": weightField,
+		},
+	}, nil
+}
+
+// isFieldOfNestedPath checks if the field is a field of the nested documents at the given path,
+// which can be read in the nested context of an aggregation on that path. Any field is valid out of nested documents.
+func isFieldOfNestedPath(field string, path string) bool {
+	return path == "" || strings.HasPrefix(field, path+".")
+}
+
+// extractTopMetrics returns the value of the field of the first document of a `top_metrics` aggregation,
+// or nil if there are no documents.
+func extractTopMetrics(record map[string]interface{}) interface{} {
+	top, _ := record["top"].([]interface{})
+	if len(top) == 0 {
+		return nil
+	}
+	first, _ := top[0].(map[string]interface{})
+	metrics, _ := first["metrics"].(map[string]interface{})
+	// the metrics of the aggregation have a single field
+	for _, value := range metrics {
+		return value
+	}
+	return nil
+}
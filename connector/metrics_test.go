@@ -0,0 +1,111 @@
+package connector
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractTopMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		want   interface{}
+	}{
+		{
+			name:   "top_document",
+			record: `{"top": [{"sort": ["2024-01-02T00:00:00.000Z"], "metrics": {"transaction_details.price": 12.5}}]}`,
+			want:   12.5,
+		},
+		{
+			name:   "no_documents",
+			record: `{"top": []}`,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var record map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.record), &record))
+			assert.Equal(t, tt.want, extractTopMetrics(record))
+		})
+	}
+}
+
+func TestPrepareMetricsOptionsOnNestedFields(t *testing.T) {
+	settings := &types.AggregationSettings{
+		TopMetricsSort: map[string]types.TopMetricsSort{
+			"transactions": {Field: "timestamp"},
+			"payments":     {Field: "transaction_details.timestamp"},
+		},
+		WeightFields: map[string]string{
+			"transactions": "quantity",
+			"payments":     "transaction_details.quantity",
+		},
+	}
+
+	_, err := prepareTopMetricsOptions("transaction_details.price", "transaction_details", "transactions", settings)
+	assert.Error(t, err)
+	_, err = prepareWeightedAvgOptions("transaction_details.price", "transaction_details", "transactions", settings)
+	assert.Error(t, err)
+
+	_, err = prepareTopMetricsOptions("transaction_details.price", "transaction_details", "payments", settings)
+	assert.NoError(t, err)
+	_, err = prepareWeightedAvgOptions("transaction_details.price", "transaction_details", "payments", settings)
+	assert.NoError(t, err)
+
+	_, err = prepareTopMetricsOptions("price", "", "transactions", settings)
+	assert.NoError(t, err)
+	_, err = prepareWeightedAvgOptions("price", "", "transactions", settings)
+	assert.NoError(t, err)
+}
+
+func TestRemoveUnconfiguredAggregateFunctions(t *testing.T) {
+	aggregateFunctions := schema.ScalarTypeAggregateFunctions{
+		"max":              {ResultType: schema.NewNamedType("double").Encode()},
+		"percentile_ranks": {ResultType: schema.NewArrayType(schema.NewNamedType("percentile_rank")).Encode()},
+		"top_metrics":      {ResultType: schema.NewNamedType("double").Encode()},
+		"weighted_avg":     {ResultType: schema.NewNamedType("double").Encode()},
+	}
+
+	tests := []struct {
+		name     string
+		settings *types.AggregationSettings
+		want     []string
+	}{
+		{
+			name:     "no_settings",
+			settings: nil,
+			want:     []string{"max"},
+		},
+		{
+			name: "configured_settings",
+			settings: &types.AggregationSettings{
+				PercentileRankValues: []float64{100},
+				TopMetricsSort:       map[string]types.TopMetricsSort{"transactions": {Field: "timestamp"}},
+			},
+			want: []string{"max", "percentile_ranks", "top_metrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ndcSchema := &schema.SchemaResponse{
+				ScalarTypes: schema.SchemaResponseScalarTypes{
+					"double": {AggregateFunctions: aggregateFunctions},
+				},
+			}
+			removeUnconfiguredAggregateFunctions(ndcSchema, tt.settings)
+
+			assert.ElementsMatch(t, tt.want, slices.Collect(maps.Keys(ndcSchema.ScalarTypes["double"].AggregateFunctions)))
+			// the shared aggregate functions are not modified
+			assert.Len(t, aggregateFunctions, 4)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package connector
+
+import (
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// prepareMoreLikeThis prepares the more_like_this query of the `more_like_this` collection argument.
+// If the argument is a variable, the query is built once the variable is replaced (see replaceVariables).
+// In queries with variables, the query of each variable set is in filter context, so it filters the similar documents without ranking them.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-mlt-query.html
+func prepareMoreLikeThis(arguments map[string]schema.Argument, index string) (interface{}, error) {
+	arg, ok := arguments["more_like_this"]
+	if !ok {
+		return nil, nil
+	}
+	value, err := evalArgument(&arg)
+	if err != nil {
+		return nil, err
+	}
+	if variable, ok := value.(types.Variable); ok {
+		return types.MoreLikeThisVariable{
+			Name:  variable,
+			Index: index,
+		}, nil
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return buildMoreLikeThisQuery(value, index)
+}
+
+// buildMoreLikeThisQuery builds a more_like_this query from the options of the `more_like_this` argument.
+// The documents are liked by `_id` in the given index, so the ids must be the ids of documents of the collection.
+func buildMoreLikeThisQuery(value interface{}, index string) (map[string]interface{}, error) {
+	options, ok := removeNullOptions(value).(map[string]interface{})
+	if !ok {
+		return nil, schema.UnprocessableContentError("invalid more_like_this argument, expected an object", map[string]any{
+			"value": value,
+		})
+	}
+
+	like := make([]interface{}, 0)
+	if ids, ok := options["ids"].([]interface{}); ok {
+		for _, id := range ids {
+			like = append(like, map[string]interface{}{
+				"_index": index,
+				"_id":    id,
+			})
+		}
+	}
+	if text, ok := options["text"].(string); ok && text != "" {
+		like = append(like, text)
+	}
+	if len(like) == 0 {
+		return nil, schema.UnprocessableContentError("ids or text is required in the more_like_this argument", nil)
+	}
+
+	delete(options, "ids")
+	delete(options, "text")
+	options["like"] = like
+
+	return map[string]interface{}{
+		"more_like_this": options,
+	}, nil
+}
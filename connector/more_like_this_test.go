@@ -0,0 +1,59 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareMoreLikeThis(t *testing.T) {
+	tests := []struct {
+		name     string
+		argument string
+		want     interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "ids_and_text",
+			argument: `{"type": "literal", "value": {"ids": ["1"], "text": "sea view", "min_term_freq": null}}`,
+			want: map[string]interface{}{
+				"more_like_this": map[string]interface{}{
+					"like": []interface{}{map[string]interface{}{"_index": "bookings", "_id": "1"}, "sea view"},
+				},
+			},
+		},
+		{
+			name:     "null",
+			argument: `{"type": "literal", "value": null}`,
+			want:     nil,
+		},
+		{
+			name:     "without_ids_or_text",
+			argument: `{"type": "literal", "value": {"fields": ["description"]}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "variable",
+			argument: `{"type": "variable", "name": "$similar"}`,
+			want:     types.MoreLikeThisVariable{Name: "$similar", Index: "bookings"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var argument schema.Argument
+			assert.NoError(t, json.Unmarshal([]byte(tt.argument), &argument))
+
+			query, err := prepareMoreLikeThis(map[string]schema.Argument{"more_like_this": argument}, "bookings")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, query)
+		})
+	}
+}
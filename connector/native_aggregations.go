@@ -0,0 +1,312 @@
+package connector
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// nativeValueAggregationTypes are the result types of the single-value metric aggregations, which are returned as their value.
+var nativeValueAggregationTypes = map[string]string{
+	"avg":                       "double",
+	"sum":                       "double",
+	"min":                       "double",
+	"max":                       "double",
+	"median_absolute_deviation": "double",
+	"weighted_avg":              "double",
+	"value_count":               "integer",
+	"cardinality":               "integer",
+}
+
+// nativeObjectAggregationTypes are the result types of the multi-value metric aggregations,
+// which are the object types of the results of the aggregate functions.
+var nativeObjectAggregationTypes = map[string]string{
+	"stats":          "stats",
+	"extended_stats": "extended_stats",
+	"string_stats":   "string_stats",
+	"geo_bounds":     "geojson_polygon",
+	"geo_centroid":   "geojson_point",
+}
+
+// nativeSingleBucketAggregationTypes are the bucket aggregations which return a single bucket, with its document count and sub-aggregations.
+var nativeSingleBucketAggregationTypes = map[string]bool{
+	"filter":              true,
+	"global":              true,
+	"missing":             true,
+	"nested":              true,
+	"reverse_nested":      true,
+	"sampler":             true,
+	"diversified_sampler": true,
+	"random_sampler":      true,
+}
+
+// nativeBucketKeyTypes are the default types of the keys of the buckets of the multi-bucket aggregations.
+// The keys of dates are epoch milliseconds, formatted in the `key_as_string` of the buckets.
+var nativeBucketKeyTypes = map[string]string{
+	"terms":                    "keyword",
+	"rare_terms":               "keyword",
+	"significant_terms":        "keyword",
+	"significant_text":         "keyword",
+	"histogram":                "double",
+	"variable_width_histogram": "double",
+	"date_histogram":           "long",
+	"auto_date_histogram":      "long",
+	"range":                    "keyword",
+	"date_range":               "keyword",
+	"geo_distance":             "keyword",
+	"ip_range":                 "keyword",
+	"filters":                  "keyword",
+	"adjacency_matrix":         "keyword",
+	"geohash_grid":             "keyword",
+	"geotile_grid":             "keyword",
+	"geohex_grid":              "keyword",
+	"composite":                "json",
+	"multi_terms":              "json",
+}
+
+// nativeBucketFields are the fields of the buckets of the aggregations, which can't be the names of their sub-aggregations.
+var nativeBucketFields = map[string]bool{
+	"key":            true,
+	"key_as_string":  true,
+	"doc_count":      true,
+	"from":           true,
+	"to":             true,
+	"from_as_string": true,
+	"to_as_string":   true,
+	"score":          true,
+	"bg_count":       true,
+}
+
+// IsNativeBucketAggregationType returns whether the results of an aggregation of a native query are buckets, which can have sub-aggregations.
+func IsNativeBucketAggregationType(aggregationType string) bool {
+	_, ok := nativeBucketKeyTypes[aggregationType]
+	return ok || nativeSingleBucketAggregationTypes[aggregationType]
+}
+
+// IsNativeBucketField returns whether a name is the name of a field of the buckets, which can't be the name of a sub-aggregation.
+func IsNativeBucketField(name string) bool {
+	return nativeBucketFields[name]
+}
+
+// addNativeAggregationsObjectType adds the object type of the row of a native query of kind `aggregations`, with a field for each aggregation.
+// The object types of the results of its bucket aggregations are named after the query and the path of the aggregation,
+// e.g. `sales_by_currency_bucket` for the buckets of the `by_currency` aggregation of the `sales` query.
+func addNativeAggregationsObjectType(ndcSchema *schema.SchemaResponse, queryName string, aggregations map[string]types.NativeAggregation) {
+	ndcSchema.ObjectTypes[queryName] = schema.ObjectType{
+		Description: utils.ToPtr("The aggregations of the " + queryName + " native query."),
+		Fields:      getNativeAggregationFields(ndcSchema, queryName, aggregations),
+	}
+}
+
+// getNativeAggregationFields returns the fields of the results of the given aggregations.
+func getNativeAggregationFields(ndcSchema *schema.SchemaResponse, typeName string, aggregations map[string]types.NativeAggregation) schema.ObjectTypeFields {
+	fields := make(schema.ObjectTypeFields, len(aggregations))
+	for name, aggregation := range aggregations {
+		fields[name] = schema.ObjectField{
+			Description: utils.ToPtr("The result of the `" + aggregation.Type + "` aggregation."),
+			Type:        getNativeAggregationType(ndcSchema, typeName+"_"+name, aggregation).Encode(),
+		}
+	}
+	return fields
+}
+
+// getNativeAggregationType returns the type of the result of an aggregation, and adds the object types of the buckets of a bucket aggregation.
+// The results of the other aggregations are untyped JSON objects.
+func getNativeAggregationType(ndcSchema *schema.SchemaResponse, typeName string, aggregation types.NativeAggregation) schema.TypeEncoder {
+	if valueType, ok := nativeValueAggregationTypes[aggregation.Type]; ok {
+		return schema.NewNullableNamedType(valueType)
+	}
+	if objectType, ok := nativeObjectAggregationTypes[aggregation.Type]; ok {
+		return schema.NewNullableNamedType(objectType)
+	}
+
+	if nativeSingleBucketAggregationTypes[aggregation.Type] {
+		fields := getNativeAggregationFields(ndcSchema, typeName, aggregation.Aggregations)
+		fields["doc_count"] = schema.ObjectField{
+			Description: utils.ToPtr("The number of documents in the bucket."),
+			Type:        schema.NewNamedType("integer").Encode(),
+		}
+		ndcSchema.ObjectTypes[typeName] = schema.ObjectType{
+			Description: utils.ToPtr("The bucket of a `" + aggregation.Type + "` aggregation."),
+			Fields:      fields,
+		}
+		return schema.NewNullableNamedType(typeName)
+	}
+
+	keyType, ok := nativeBucketKeyTypes[aggregation.Type]
+	if !ok {
+		return schema.NewNullableNamedType("json")
+	}
+	if aggregation.KeyType != "" {
+		keyType = aggregation.KeyType
+	}
+	fields := getNativeAggregationFields(ndcSchema, typeName, aggregation.Aggregations)
+	fields["key"] = schema.ObjectField{
+		Description: utils.ToPtr("The key of the bucket."),
+		Type:        schema.NewNullableNamedType(keyType).Encode(),
+	}
+	fields["key_as_string"] = schema.ObjectField{
+		Description: utils.ToPtr("The formatted key of the bucket, for the keys of dates."),
+		Type:        schema.NewNullableNamedType("keyword").Encode(),
+	}
+	fields["doc_count"] = schema.ObjectField{
+		Description: utils.ToPtr("The number of documents in the bucket."),
+		Type:        schema.NewNamedType("integer").Encode(),
+	}
+	switch aggregation.Type {
+	case "range", "date_range", "geo_distance":
+		for _, bound := range []string{"from", "to"} {
+			fields[bound] = schema.ObjectField{
+				Description: utils.ToPtr("The " + bound + " bound of the range, as a number (epoch milliseconds for dates)."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			}
+			fields[bound+"_as_string"] = schema.ObjectField{
+				Description: utils.ToPtr("The formatted " + bound + " bound of the range, for date ranges."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			}
+		}
+	case "ip_range":
+		for _, bound := range []string{"from", "to"} {
+			fields[bound] = schema.ObjectField{
+				Description: utils.ToPtr("The " + bound + " bound of the IP range."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			}
+		}
+	case "significant_terms", "significant_text":
+		fields["score"] = schema.ObjectField{
+			Description: utils.ToPtr("The significance score of the term."),
+			Type:        schema.NewNullableNamedType("double").Encode(),
+		}
+		fields["bg_count"] = schema.ObjectField{
+			Description: utils.ToPtr("The number of documents of the background set with the term."),
+			Type:        schema.NewNullableNamedType("integer").Encode(),
+		}
+	}
+	ndcSchema.ObjectTypes[typeName+"_bucket"] = schema.ObjectType{
+		Description: utils.ToPtr("A bucket of a `" + aggregation.Type + "` aggregation."),
+		Fields:      fields,
+	}
+	return schema.NewNullableType(schema.NewArrayType(schema.NewNamedType(typeName + "_bucket")))
+}
+
+// extractNativeAggregations returns the row of the aggregations of a native query of kind `aggregations`, with the selected fields.
+func extractNativeAggregations(aggregations map[string]interface{}, postProcessor *types.PostProcessor) map[string]interface{} {
+	row := make(map[string]interface{}, len(postProcessor.NativeAggregationFields))
+	for fieldName, field := range postProcessor.NativeAggregationFields {
+		column, err := field.AsColumn()
+		if err != nil {
+			continue
+		}
+		aggregation, ok := postProcessor.NativeAggregations[column.Column]
+		if !ok {
+			row[fieldName] = nil
+			continue
+		}
+		record, _ := aggregations[column.Column].(map[string]interface{})
+		row[fieldName] = selectNestedFields(extractNativeAggregation(record, aggregation), column.Fields)
+	}
+	return row
+}
+
+// extractNativeAggregation converts the result of an aggregation into the value of its result type (see getNativeAggregationType).
+func extractNativeAggregation(record map[string]interface{}, aggregation types.NativeAggregation) interface{} {
+	if record == nil {
+		return nil
+	}
+	if _, ok := nativeValueAggregationTypes[aggregation.Type]; ok {
+		return record["value"]
+	}
+	switch aggregation.Type {
+	case "geo_bounds":
+		return extractGeoBounds(record)
+	case "geo_centroid":
+		return extractGeoCentroid(record)
+	}
+	if nativeSingleBucketAggregationTypes[aggregation.Type] {
+		return extractNativeBucket(record, aggregation)
+	}
+	if _, ok := nativeBucketKeyTypes[aggregation.Type]; !ok {
+		return record
+	}
+
+	var buckets []interface{}
+	switch recordBuckets := record["buckets"].(type) {
+	case []interface{}:
+		buckets = recordBuckets
+	case map[string]interface{}:
+		// the buckets of keyed aggregations (e.g. `filters`) are returned by key
+		for _, key := range slices.Sorted(maps.Keys(recordBuckets)) {
+			bucket, ok := recordBuckets[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if _, ok := bucket["key"]; !ok {
+				bucket["key"] = key
+			}
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	results := make([]interface{}, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket, ok := bucket.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		results = append(results, extractNativeBucket(bucket, aggregation))
+	}
+	return results
+}
+
+// extractNativeBucket returns the fields of a bucket, and the results of its sub-aggregations.
+func extractNativeBucket(bucket map[string]interface{}, aggregation types.NativeAggregation) map[string]interface{} {
+	result := make(map[string]interface{}, len(aggregation.Aggregations)+len(nativeBucketFields))
+	for name, subAggregation := range aggregation.Aggregations {
+		record, _ := bucket[name].(map[string]interface{})
+		result[name] = extractNativeAggregation(record, subAggregation)
+	}
+	for field := range nativeBucketFields {
+		if value, ok := bucket[field]; ok {
+			result[field] = value
+		}
+	}
+	return result
+}
+
+// selectNestedFields returns the fields of a value selected by a nested field of a query, in the objects and arrays of the value.
+func selectNestedFields(value interface{}, field schema.NestedField) interface{} {
+	if field == nil || value == nil {
+		return value
+	}
+	switch nestedField := field.Interface().(type) {
+	case *schema.NestedObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		selected := make(map[string]interface{}, len(nestedField.Fields))
+		for fieldName, subField := range nestedField.Fields {
+			column, err := subField.AsColumn()
+			if err != nil {
+				continue
+			}
+			selected[fieldName] = selectNestedFields(object[column.Column], column.Fields)
+		}
+		return selected
+	case *schema.NestedArray:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		selected := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			selected = append(selected, selectNestedFields(value, nestedField.Fields))
+		}
+		return selected
+	default:
+		return value
+	}
+}
@@ -0,0 +1,173 @@
+package connector
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// salesAggregations are the aggregations of a native query of kind `aggregations` used in the tests.
+var salesAggregations = map[string]types.NativeAggregation{
+	"total": {Type: "sum"},
+	"by_currency": {
+		Type: "terms",
+		Aggregations: map[string]types.NativeAggregation{
+			"per_day": {
+				Type: "date_histogram",
+				Aggregations: map[string]types.NativeAggregation{
+					"average": {Type: "avg"},
+				},
+			},
+		},
+	},
+	"by_status": {Type: "filters"},
+	"refunds": {
+		Type: "filter",
+		Aggregations: map[string]types.NativeAggregation{
+			"count": {Type: "value_count"},
+		},
+	},
+}
+
+func TestAddNativeAggregationsObjectType(t *testing.T) {
+	ndcSchema := &schema.SchemaResponse{ObjectTypes: schema.SchemaResponseObjectTypes{}}
+	addNativeAggregationsObjectType(ndcSchema, "sales", salesAggregations)
+
+	assert.ElementsMatch(t, []string{
+		"sales",
+		"sales_by_currency_bucket",
+		"sales_by_currency_per_day_bucket",
+		"sales_by_status_bucket",
+		"sales_refunds",
+	}, slices.Collect(maps.Keys(ndcSchema.ObjectTypes)))
+
+	tests := []struct {
+		typeName  string
+		fieldName string
+		want      schema.TypeEncoder
+	}{
+		{"sales", "total", schema.NewNullableNamedType("double")},
+		{"sales", "by_currency", schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("sales_by_currency_bucket")))},
+		{"sales", "refunds", schema.NewNullableNamedType("sales_refunds")},
+		{"sales_by_currency_bucket", "key", schema.NewNullableNamedType("keyword")},
+		{"sales_by_currency_bucket", "per_day", schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("sales_by_currency_per_day_bucket")))},
+		{"sales_by_currency_per_day_bucket", "key", schema.NewNullableNamedType("long")},
+		{"sales_by_currency_per_day_bucket", "average", schema.NewNullableNamedType("double")},
+		{"sales_refunds", "doc_count", schema.NewNamedType("integer")},
+		{"sales_refunds", "count", schema.NewNullableNamedType("integer")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typeName+"."+tt.fieldName, func(t *testing.T) {
+			field, ok := ndcSchema.ObjectTypes[tt.typeName].Fields[tt.fieldName]
+			assert.True(t, ok)
+			assert.Equal(t, tt.want.Encode(), field.Type)
+		})
+	}
+}
+
+func TestExtractNativeAggregations(t *testing.T) {
+	aggregations := `{
+  "total": {"value": 150.5},
+  "by_currency": {
+    "buckets": [
+      {
+        "key": "USD",
+        "doc_count": 3,
+        "per_day": {
+          "buckets": [
+            {"key_as_string": "2024-01-01T00:00:00.000Z", "key": 1704067200000, "doc_count": 2, "average": {"value": 40}},
+            {"key_as_string": "2024-01-02T00:00:00.000Z", "key": 1704153600000, "doc_count": 1, "average": {"value": null}}
+          ]
+        }
+      }
+    ]
+  },
+  "by_status": {
+    "buckets": {
+      "paid": {"doc_count": 4},
+      "failed": {"doc_count": 1}
+    }
+  },
+  "refunds": {"doc_count": 2, "count": {"value": 2}, "meta": {"source": "native"}}
+}`
+	fields := `{
+  "total": {"type": "column", "column": "total"},
+  "currencies": {
+    "type": "column",
+    "column": "by_currency",
+    "fields": {
+      "type": "array",
+      "fields": {
+        "type": "object",
+        "fields": {
+          "currency": {"type": "column", "column": "key"},
+          "days": {
+            "type": "column",
+            "column": "per_day",
+            "fields": {
+              "type": "array",
+              "fields": {
+                "type": "object",
+                "fields": {
+                  "day": {"type": "column", "column": "key_as_string"},
+                  "average": {"type": "column", "column": "average"}
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "by_status": {
+    "type": "column",
+    "column": "by_status",
+    "fields": {
+      "type": "array",
+      "fields": {
+        "type": "object",
+        "fields": {
+          "key": {"type": "column", "column": "key"},
+          "doc_count": {"type": "column", "column": "doc_count"}
+        }
+      }
+    }
+  },
+  "refunds": {"type": "column", "column": "refunds"}
+}`
+	want := `{
+  "total": 150.5,
+  "currencies": [
+    {
+      "currency": "USD",
+      "days": [
+        {"day": "2024-01-01T00:00:00.000Z", "average": 40},
+        {"day": "2024-01-02T00:00:00.000Z", "average": null}
+      ]
+    }
+  ],
+  "by_status": [
+    {"key": "failed", "doc_count": 1},
+    {"key": "paid", "doc_count": 4}
+  ],
+  "refunds": {"doc_count": 2, "count": 2}
+}`
+
+	var aggregationsData map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(aggregations), &aggregationsData))
+	var queryFields schema.QueryFields
+	assert.NoError(t, json.Unmarshal([]byte(fields), &queryFields))
+
+	postProcessor := &types.PostProcessor{
+		NativeAggregations:      salesAggregations,
+		NativeAggregationFields: queryFields,
+	}
+	got, err := json.Marshal(extractNativeAggregations(aggregationsData, postProcessor))
+	assert.NoError(t, err)
+	assert.JSONEq(t, want, string(got))
+}
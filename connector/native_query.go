@@ -37,6 +37,13 @@ func handleNativeQuery(
 		}
 	}
 
+	if queryConfig.ReturnType != nil && queryConfig.ReturnType.Kind == "aggregations" {
+		// the aggregations are returned as the columns of a single row, instead of the hits
+		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+		postProcessor.NativeAggregations = queryConfig.ReturnType.Aggregations
+		query["size"] = 0
+	}
+
 	return prepareNativeQuery(ctx, nativeQuery, query), nil
 }
 
@@ -53,7 +60,9 @@ func prepareNativeQuery(
 	if aggs, ok := nativeQuery["aggs"].(map[string]interface{}); ok {
 		if aggregates, ok := query["aggs"].(map[string]interface{}); ok {
 			for aggregateName, aggregate := range aggs {
-				postProcessor.ColumnAggregate[aggregateName] = false
+				if postProcessor.NativeAggregations == nil {
+					postProcessor.ColumnAggregate[aggregateName] = false
+				}
 				aggregates[aggregateName] = aggregate
 			}
 		} else {
@@ -64,19 +73,15 @@ func prepareNativeQuery(
 	// Merge filters
 	if nqFilters, ok := nativeQuery["query"]; ok {
 		if filters, ok := query["query"]; ok {
-			// append native query filters to the boolean query if already present
-			if boolFilter, ok := filters.(map[string]interface{})["bool"]; ok {
-				if mustFilter, ok := boolFilter.(map[string]interface{})["must"].([]interface{}); ok {
-					query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"] = append(mustFilter, nqFilters)
-				}
-			} else {
-				// create new boolean query if not present
-				query["query"] = map[string]interface{}{
-					"bool": map[string]interface{}{
-						"must": []interface{}{filters, nqFilters},
-					},
-				}
+			// the predicate may be a boolean query in filter context (or a "should" clause),
+			// so it is AND-ed with the native query filters instead of appending to its clauses
+			query["query"] = map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must": []interface{}{filters, nqFilters},
+				},
 			}
+		} else {
+			query["query"] = nqFilters
 		}
 	}
 
@@ -0,0 +1,80 @@
+package connector
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// addPercentilesObjectType adds the result type of the `percentiles` aggregate function, which has a field for each configured percent,
+// e.g. `p50` and `p99_9` for the 50th and the 99.9th percentiles.
+func addPercentilesObjectType(ndcSchema *schema.SchemaResponse, settings *types.AggregationSettings) {
+	fields := make(schema.ObjectTypeFields)
+	for _, percent := range settings.GetPercents() {
+		fields[getPercentileFieldName(percent)] = schema.ObjectField{
+			Description: utils.ToPtr("The " + strconv.FormatFloat(percent, 'f', -1, 64) + "th percentile, null if there are no values."),
+			Type:        schema.NewNullableNamedType("double").Encode(),
+		}
+	}
+	ndcSchema.ObjectTypes["percentiles"] = schema.ObjectType{
+		Description: utils.ToPtr("The configured percentiles of the values of a column."),
+		Fields:      fields,
+	}
+}
+
+// getPercentileFieldName returns the name of the field of a percentile in the `percentiles` object type.
+func getPercentileFieldName(percent float64) string {
+	return "p" + strings.ReplaceAll(strconv.FormatFloat(percent, 'f', -1, 64), ".", "_")
+}
+
+// preparePercentilesOptions adds the configured percents (or values) to a `percentiles` (or `percentile_ranks`) aggregation.
+// The results are requested as an array of key and value, rather than an object keyed by the string representation of the keys.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-percentile-aggregation.html
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-percentile-rank-aggregation.html
+func preparePercentilesOptions(options map[string]interface{}, function string, settings *types.AggregationSettings) error {
+	switch function {
+	case "percentiles":
+		options["percents"] = settings.GetPercents()
+	case "percentile_ranks":
+		values := settings.GetPercentileRankValues()
+		if len(values) == 0 {
+			return schema.UnprocessableContentError("percentile_ranks requires the 'percentile_rank_values' aggregation setting in the configuration", nil)
+		}
+		options["values"] = values
+	default:
+		return nil
+	}
+	options["keyed"] = false
+	return nil
+}
+
+// extractPercentiles converts the result of a `percentiles` aggregation into a `percentiles` object,
+// and the result of a `percentile_ranks` aggregation into an array of `percentile_rank` objects.
+func extractPercentiles(record map[string]interface{}, function string) interface{} {
+	values, _ := record["values"].([]interface{})
+	if function == "percentile_ranks" {
+		ranks := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			value, _ := value.(map[string]interface{})
+			ranks = append(ranks, map[string]interface{}{
+				"value":   value["key"],
+				"percent": value["value"],
+			})
+		}
+		return ranks
+	}
+
+	percentiles := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		value, _ := value.(map[string]interface{})
+		percent, ok := value["key"].(float64)
+		if !ok {
+			continue
+		}
+		percentiles[getPercentileFieldName(percent)] = value["value"]
+	}
+	return percentiles
+}
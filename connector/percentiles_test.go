@@ -0,0 +1,77 @@
+package connector
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractPercentiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		record   string
+		want     string
+	}{
+		{
+			name:     "percentiles",
+			function: "percentiles",
+			record:   `{"values": [{"key": 50.0, "value": 12.5}, {"key": 99.9, "value": 120.0}]}`,
+			want:     `{"p50": 12.5, "p99_9": 120.0}`,
+		},
+		{
+			name:     "percentiles_without_values",
+			function: "percentiles",
+			record:   `{"values": [{"key": 50.0, "value": null}]}`,
+			want:     `{"p50": null}`,
+		},
+		{
+			name:     "percentile_ranks",
+			function: "percentile_ranks",
+			record:   `{"values": [{"key": 100.0, "value": 42.1}, {"key": 500.0, "value": 97.3}]}`,
+			want:     `[{"value": 100.0, "percent": 42.1}, {"value": 500.0, "percent": 97.3}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var record map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.record), &record))
+
+			got, err := json.Marshal(extractPercentiles(record, tt.function))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestAddPercentilesObjectType(t *testing.T) {
+	newSchema := func() *schema.SchemaResponse {
+		return &schema.SchemaResponse{
+			ObjectTypes: make(schema.SchemaResponseObjectTypes),
+		}
+	}
+
+	t.Run("default_percents", func(t *testing.T) {
+		ndcSchema := newSchema()
+		addPercentilesObjectType(ndcSchema, nil)
+
+		fields := ndcSchema.ObjectTypes["percentiles"].Fields
+		assert.ElementsMatch(t, []string{"p50", "p90", "p95", "p99"}, slices.Collect(maps.Keys(fields)))
+	})
+
+	t.Run("configured_percents", func(t *testing.T) {
+		ndcSchema := newSchema()
+		addPercentilesObjectType(ndcSchema, &types.AggregationSettings{
+			Percents: []float64{25, 99.9},
+		})
+
+		fields := ndcSchema.ObjectTypes["percentiles"].Fields
+		assert.ElementsMatch(t, []string{"p25", "p99_9"}, slices.Collect(maps.Keys(fields)))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"maps"
+	"sort"
 
 	"github.com/hasura/ndc-elasticsearch/elasticsearch"
 	"github.com/hasura/ndc-elasticsearch/types"
@@ -41,6 +42,11 @@ func executeQuery(ctx context.Context, state *types.State, request *schema.Query
 	rowSets := make([]schema.RowSet, 0)
 	index := request.Collection
 
+	// Execute the suggest function of an index
+	if suggestIndex, ok := getSuggestFunctionIndex(state.Schema, request.Collection); ok {
+		return executeSuggestFunction(ctx, state, request, suggestIndex)
+	}
+
 	// Identify the index from configuration
 	nativeQueries := state.Configuration.Queries
 	queryConfig, ok := nativeQueries[request.Collection]
@@ -69,6 +75,11 @@ func executeQuery(ctx context.Context, state *types.State, request *schema.Query
 
 	// Prepare query with variables if present
 	if len(request.Variables) != 0 {
+		if postProcessor := ctx.Value("postProcessor").(*types.PostProcessor); len(postProcessor.ExactDistinctCounts) != 0 {
+			// the aggregations of the variable sets are sub-aggregations, which can't be composite aggregations
+			return nil, schema.UnprocessableContentError("exact distinct counts are not supported in queries with variables", nil)
+		}
+
 		_, variableSpan := state.Tracer.Start(ctx, "prepare_query_with_variables")
 		defer variableSpan.End()
 
@@ -101,6 +112,18 @@ func executeQuery(ctx context.Context, state *types.State, request *schema.Query
 	}
 	searchSpan.End()
 
+	// Count the distinct values of the exact distinct counts, page by page
+	if postProcessor := ctx.Value("postProcessor").(*types.PostProcessor); len(postProcessor.ExactDistinctCounts) != 0 {
+		countContext, countSpan := state.Tracer.Start(ctx, "count_exact_distinct_values")
+		defer countSpan.End()
+
+		if err := countExactDistinctValues(countContext, state, index, dslQuery, res); err != nil {
+			countSpan.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		countSpan.End()
+	}
+
 	// Prepare response based on variables
 	if len(request.Variables) != 0 {
 		responseContext, responseSpan := state.Tracer.Start(ctx, "prepare_ndc_response")
@@ -139,15 +162,33 @@ func prepareElasticsearchQuery(ctx context.Context, request *schema.QueryRequest
 
 	span.AddEvent("prepare_select_query")
 	// Select the fields
-	if len(request.Query.Fields) != 0 {
+	if queryConfig, ok := state.Configuration.Queries[request.Collection]; ok && queryConfig.ReturnType.Kind == "aggregations" {
+		// the fields of a native query of kind `aggregations` are its aggregations, which are extracted from the response
+		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
+		postProcessor.IsFields = len(request.Query.Fields) != 0
+		postProcessor.NativeAggregationFields = request.Query.Fields
+	} else if len(request.Query.Fields) != 0 {
 		postProcessor := ctx.Value("postProcessor").(*types.PostProcessor)
-		postProcessor.IsFields = true
-		source, selectedFields, err := prepareSelectFields(ctx, request.Query.Fields, postProcessor, "")
+		selection, err := prepareSelection(ctx, request.Query.Fields, postProcessor, state, index)
 		if err != nil {
 			return nil, err
 		}
-		postProcessor.SelectedFields = selectedFields
-		query["_source"] = source
+		maps.Copy(query, selection)
+	}
+
+	// Runtime fields are defined in the request, so that they can be selected, filtered, sorted and aggregated like regular fields
+	if runtimeMappings := state.Configuration.GetRuntimeMappings(index); len(runtimeMappings) != 0 {
+		query["runtime_mappings"] = runtimeMappings
+	}
+
+	span.AddEvent("prepare_collapse_query")
+	// Collapse
+	collapse, err := prepareCollapse(ctx, request, state, index)
+	if err != nil {
+		return nil, err
+	}
+	if collapse != nil {
+		query["collapse"] = collapse
 	}
 
 	span.AddEvent("prepare_paginate_query")
@@ -181,9 +222,20 @@ func prepareElasticsearchQuery(ctx context.Context, request *schema.QueryRequest
 	}
 
 	span.AddEvent("prepare_aggregate_query")
+	// The mode of the distinct counts of the aggregates, including the aggregates of the groups
+	distinctCount, err := getDistinctCountOptions(request.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "distinctCount", distinctCount)
+
 	// Aggregations
 	if request.Query.Aggregates != nil {
-		aggs, err := prepareAggregateQuery(ctx, request.Query.Aggregates, state, index)
+		predicates, err := getAggregatePredicates(request.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		aggs, err := prepareAggregateQuery(ctx, request.Query.Aggregates, predicates, state, index)
 		if err != nil {
 			return nil, err
 		}
@@ -202,6 +254,24 @@ func prepareElasticsearchQuery(ctx context.Context, request *schema.QueryRequest
 		query["track_total_hits"] = true
 	}
 
+	span.AddEvent("prepare_group_by_query")
+	// Group by
+	groups, err := prepareGroupBy(ctx, request, state, index)
+	if err != nil {
+		return nil, err
+	}
+	if groups != nil {
+		aggs, ok := query["aggs"].(map[string]interface{})
+		if !ok {
+			aggs = make(map[string]interface{})
+		}
+		aggs[groupsAggregationName] = groups
+		query["aggs"] = aggs
+
+		// the groups are returned as rows, instead of the documents
+		query["size"] = 0
+	}
+
 	span.AddEvent("prepare_filter_query")
 	// Filter
 	if request.Query.Predicate != nil {
@@ -209,14 +279,143 @@ func prepareElasticsearchQuery(ctx context.Context, request *schema.QueryRequest
 		if err != nil {
 			return nil, err
 		}
+		if len(filter) != 0 && len(request.Variables) == 0 {
+			// queries with variables are executed inside a filters aggregation, which is already in filter context
+			filter = toFilterContext(filter)
+		}
 		if len(filter) != 0 {
 			query["query"] = filter
 		}
 	}
 
+	// Scripts
+	scriptFilters := prepareScriptFilters(request.Arguments, state, index)
+	if len(scriptFilters) != 0 {
+		boolQuery := map[string]interface{}{
+			"filter": scriptFilters,
+		}
+		if filter, ok := query["query"]; ok {
+			boolQuery["must"] = []interface{}{filter}
+		}
+		query["query"] = map[string]interface{}{
+			"bool": boolQuery,
+		}
+	}
+
+	// More like this
+	moreLikeThis, err := prepareMoreLikeThis(request.Arguments, index)
+	if err != nil {
+		return nil, err
+	}
+	if moreLikeThis != nil {
+		// the similar documents are ranked by the more_like_this query, and only filtered by the predicates
+		boolQuery := map[string]interface{}{
+			"must": []interface{}{moreLikeThis},
+		}
+		if filter, ok := query["query"]; ok {
+			boolQuery["filter"] = []interface{}{filter}
+		}
+		query["query"] = map[string]interface{}{
+			"bool": boolQuery,
+		}
+	}
+
+	// Highlight
+	if highlight := prepareHighlight(request.Arguments); highlight != nil {
+		query["highlight"] = highlight
+		if len(request.Variables) == 0 {
+			// inner hits are not returned by the top_hits aggregation used for queries with variables
+			addNestedHighlight(query["query"], highlight, make(map[string]bool))
+		}
+	}
+
 	return query, nil
 }
 
+// prepareScriptFilters returns a script query for each script argument of the request.
+// The scripts are executed by id, as stored scripts, so that the connector never executes a script sent by a client.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-script-query.html
+func prepareScriptFilters(arguments map[string]schema.Argument, state *types.State, index string) []map[string]interface{} {
+	scriptNames := make([]string, 0)
+	for argName, arg := range arguments {
+		script, ok := state.Configuration.Scripts[argName]
+		if !ok || script.Index != index || arg.Value == nil || arg.Value == false {
+			continue
+		}
+		scriptNames = append(scriptNames, argName)
+	}
+	sort.Strings(scriptNames)
+
+	filters := make([]map[string]interface{}, 0, len(scriptNames))
+	for _, scriptName := range scriptNames {
+		storedScript := map[string]interface{}{
+			"id": state.Configuration.Scripts[scriptName].GetStoredScriptId(scriptName),
+		}
+		if params, ok := arguments[scriptName].Value.(map[string]interface{}); ok {
+			storedScript["params"] = params
+		}
+		filters = append(filters, map[string]interface{}{
+			"script": map[string]interface{}{
+				"script": storedScript,
+			},
+		})
+	}
+	return filters
+}
+
+// prepareSelection prepares the options of the search which select the given fields (`_source`, `fields` and `docvalue_fields`),
+// and the post processor which extracts them from the hits.
+func prepareSelection(ctx context.Context, fields schema.QueryFields, postProcessor *types.PostProcessor, state *types.State, index string) (map[string]interface{}, error) {
+	postProcessor.IsFields = true
+	source, selectedFields, err := prepareSelectFields(ctx, fields, postProcessor, "")
+	if err != nil {
+		return nil, err
+	}
+	postProcessor.SelectedFields = selectedFields
+
+	selection := make(map[string]interface{})
+	source, fetchedFields, docValueFields := prepareFieldsSelection(source, state, index)
+	if len(fetchedFields) != 0 {
+		selection["fields"] = fetchedFields
+	}
+	if len(docValueFields) != 0 {
+		selection["docvalue_fields"] = docValueFields
+	}
+	postProcessor.FetchedFields = append(fetchedFields, docValueFields...)
+	if len(source) != 0 || len(postProcessor.FetchedFields) == 0 {
+		selection["_source"] = source
+	} else {
+		selection["_source"] = map[string]interface{}{
+			"excludes": []string{"*"},
+		}
+	}
+	return selection, nil
+}
+
+// prepareFieldsSelection splits the selected fields by the way their values are retrieved, based on the mappings of the index:
+// the fields read from `_source`, the fields retrieved with the `fields` option (e.g. runtime fields)
+// and the fields retrieved from their doc values (e.g. fields excluded from `_source`).
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-fields.html
+func prepareFieldsSelection(source []string, state *types.State, index string) ([]string, []string, []string) {
+	sourceFields := make([]string, 0, len(source))
+	fields := make([]string, 0)
+	docValueFields := make([]string, 0)
+	for _, field := range source {
+		switch state.Configuration.GetFieldRetrieval(index, field) {
+		case types.FieldsRetrieval:
+			fields = append(fields, field)
+		case types.DocValueFieldsRetrieval:
+			docValueFields = append(docValueFields, field)
+		default:
+			sourceFields = append(sourceFields, field)
+		}
+	}
+	sort.Strings(sourceFields)
+	sort.Strings(fields)
+	sort.Strings(docValueFields)
+	return sourceFields, fields, docValueFields
+}
+
 // check whether the request has collection arguments
 func hasCollectionArguments(request *schema.QueryRequest) bool {
 	return len(request.Arguments) != 0
@@ -233,5 +432,12 @@ func handleCollectionArguments(arguments map[string]schema.Argument) map[string]
 		// https://www.elastic.co/guide/en/elasticsearch/reference/current/paginate-search-results.html
 		// query["track_total_hits"] = false
 	}
+
+	// Handle min_score and track_scores
+	for _, argName := range []string{"min_score", "track_scores"} {
+		if arg, ok := arguments[argName]; ok && arg.Value != nil {
+			query[argName] = arg.Value
+		}
+	}
 	return query
 }
@@ -125,6 +125,26 @@ var tests = []test{
 		group: "customers",
 		name:  "subtype_term_clause",
 	},
+	{
+		group: "customers",
+		name:  "fuzzy_and_terms_set",
+	},
+	{
+		group: "customers",
+		name:  "intervals",
+	},
+	{
+		group: "customers",
+		name:  "match_with_options",
+	},
+	{
+		group: "customers",
+		name:  "standard_operators_on_subfield",
+	},
+	{
+		group: "customers",
+		name:  "case_insensitive_operators",
+	},
 	{
 		group: "payments",
 		name:  "cardinality_aggregation",
@@ -149,6 +169,26 @@ var tests = []test{
 		group: "payments",
 		name:  "search_after_multiple_sorts",
 	},
+	{
+		group: "payments",
+		name:  "sort_by_score",
+	},
+	{
+		group: "payments",
+		name:  "filter_by_id",
+	},
+	{
+		group: "payments",
+		name:  "filter_by_id_with_variables",
+	},
+	{
+		group: "payments",
+		name:  "standard_operators",
+	},
+	{
+		group: "payments",
+		name:  "standard_operators_with_variables",
+	},
 	{
 		group: "flights_nested_flattened",
 		name: "nested_filtering",
@@ -197,6 +237,150 @@ var tests = []test{
 		group: "flights_nested",
 		name: "empty_nested_or",
 	},
+	{
+		group: "flights_nested",
+		name:  "exists_nested_and",
+	},
+	{
+		group: "flights_nested",
+		name:  "exists_nested_or_not",
+	},
+	{
+		group: "flights_nested",
+		name:  "not_exists_nested",
+	},
+	{
+		group: "payments",
+		name:  "collapse_with_inner_hits",
+	},
+	{
+		group: "payments",
+		name:  "group_by_composite",
+	},
+	{
+		group: "payments",
+		name:  "group_by_terms",
+	},
+	{
+		group: "payments",
+		name:  "group_by_with_variables",
+	},
+	{
+		group: "payments",
+		name:  "group_by_date_histogram",
+	},
+	{
+		group: "payments",
+		name:  "group_by_date_histogram_with_variables",
+	},
+	{
+		group: "payments",
+		name:  "percentiles_aggregations",
+	},
+	{
+		group: "payments",
+		name:  "metric_aggregations",
+	},
+	{
+		group: "payments",
+		name:  "filtered_aggregates",
+	},
+	{
+		group: "payments",
+		name:  "group_by_filtered_aggregates",
+	},
+	{
+		group: "payments",
+		name:  "group_by_histogram",
+	},
+	{
+		group: "payments",
+		name:  "group_by_ranges",
+	},
+	{
+		group: "payments",
+		name:  "geo_aggregations",
+	},
+	{
+		group: "payments",
+		name:  "group_by_geohash_grid",
+	},
+	{
+		group: "payments",
+		name:  "group_by_geotile_grid",
+	},
+	{
+		group: "payments",
+		name:  "group_by_significant_terms",
+	},
+	{
+		group: "payments",
+		name:  "group_by_rare_terms",
+	},
+	{
+		group: "payments",
+		name:  "distinct_count_exact",
+	},
+	{
+		group: "payments",
+		name:  "distinct_count_exact_argument",
+	},
+	{
+		group: "payments",
+		name:  "distinct_count_precision_threshold",
+	},
+	{
+		group: "bookings",
+		name:  "range_contains",
+	},
+	{
+		group: "bookings",
+		name:  "range_within_nested",
+	},
+	{
+		group: "bookings",
+		name:  "range_term",
+	},
+	{
+		group: "bookings",
+		name:  "range_intersects_with_variables",
+	},
+	{
+		group: "bookings",
+		name:  "ip_in_cidr",
+	},
+	{
+		group: "bookings",
+		name:  "ip_range",
+	},
+	{
+		group: "bookings",
+		name:  "script_arguments",
+	},
+	{
+		group: "bookings",
+		name:  "runtime_fields",
+	},
+	{
+		group: "bookings",
+		name:  "fields_api_selection",
+	},
+	{
+		group: "bookings",
+		name:  "highlight",
+	},
+	{
+		group: "bookings",
+		name:  "search_as_you_type",
+	},
+	{
+		group: "bookings",
+		name:  "more_like_this",
+	},
+	{
+		group: "bookings",
+		name:  "more_like_this_with_variables",
+	},
 }
 
 func TestPrepareElasticsearchQuery(t *testing.T) {
@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"strings"
 
 	"github.com/hasura/ndc-elasticsearch/types"
 	"github.com/hasura/ndc-sdk-go/schema"
@@ -36,6 +37,12 @@ func prepareResponseWithVariables(ctx context.Context, res map[string]interface{
 			continue
 		}
 		rowSet := prepareResponse(ctx, docs)
+		if postProcessor.GroupBy != nil && postProcessor.IsFields {
+			rowSet.Rows = extractGroups(bucketData, postProcessor)
+		}
+		if postProcessor.NativeAggregations != nil && postProcessor.IsFields {
+			rowSet.Rows = []map[string]interface{}{extractNativeAggregations(bucketData, postProcessor)}
+		}
 
 		rowSet.Aggregates = extractAggregates(rowSet.Aggregates, bucketData, postProcessor)
 		rowSets = append(rowSets, *rowSet)
@@ -52,12 +59,7 @@ func prepareResponse(ctx context.Context, response map[string]interface{}) *sche
 
 	documents := make([]map[string]interface{}, len(hits))
 	for i, hit := range hits {
-		doc := hit.(map[string]interface{})
-		source := doc["_source"].(map[string]interface{})
-		if postProcessor.IsIDSelected {
-			source["_id"] = doc["_id"].(string)
-		}
-		documents[i] = extractDocument(source, postProcessor.SelectedFields)
+		documents[i] = extractHit(hit.(map[string]interface{}), postProcessor)
 	}
 
 	rowSet := &schema.RowSet{
@@ -68,6 +70,14 @@ func prepareResponse(ctx context.Context, response map[string]interface{}) *sche
 		rowSet.Aggregates = extractAggregates(rowSet.Aggregates, aggregations, postProcessor)
 	}
 
+	if postProcessor.GroupBy != nil {
+		documents = extractGroups(aggregations, postProcessor)
+	}
+
+	if postProcessor.NativeAggregations != nil {
+		documents = []map[string]interface{}{extractNativeAggregations(aggregations, postProcessor)}
+	}
+
 	if postProcessor.IsFields {
 		rowSet.Rows = documents
 	}
@@ -79,6 +89,71 @@ func prepareResponse(ctx context.Context, response map[string]interface{}) *sche
 	return rowSet
 }
 
+// extractHit extracts the selected fields of a hit, from its source and the other sections of the hit.
+func extractHit(hit map[string]interface{}, postProcessor *types.PostProcessor) map[string]interface{} {
+	source, ok := hit["_source"].(map[string]interface{})
+	if !ok {
+		source = make(map[string]interface{})
+	}
+	if postProcessor.IsIDSelected {
+		source["_id"] = hit["_id"].(string)
+	}
+	if postProcessor.IsScoreSelected {
+		// _score is null when the results are sorted on a field and track_scores is not set
+		source["_score"] = hit["_score"]
+	}
+	if postProcessor.IsHighlightSelected {
+		// like the other object columns, the highlight is returned as an array of one object
+		source["_highlight"] = []interface{}{extractHighlight(hit)}
+	}
+	if postProcessor.IsGroupSelected {
+		// the documents of a query without group_by are not groups
+		source["_group"] = nil
+	}
+	if postProcessor.Collapsed != nil {
+		source["_collapsed"] = extractCollapsedHits(hit, postProcessor.Collapsed)
+	}
+	if len(postProcessor.FetchedFields) != 0 {
+		extractFetchedFields(source, hit["fields"], postProcessor.FetchedFields)
+	}
+	return extractDocument(source, postProcessor.SelectedFields)
+}
+
+// extractFetchedFields adds the values of the fields retrieved with the `fields` or `docvalue_fields` options,
+// which are returned in the `fields` section of a hit, to the source.
+// The `fields` section always returns arrays, so a single value is unwrapped to match the scalar column.
+// The values of a field of an object are added to the object in the source, which is created if needed.
+func extractFetchedFields(source map[string]interface{}, hitFields interface{}, fetchedFields []string) {
+	fields, _ := hitFields.(map[string]interface{})
+	for _, fieldPath := range fetchedFields {
+		var value interface{}
+		if values, ok := fields[fieldPath].([]interface{}); ok && len(values) == 1 {
+			value = values[0]
+		} else if ok && len(values) > 1 {
+			value = values
+		}
+
+		splitFieldPath := strings.Split(fieldPath, ".")
+		object := source
+		for _, objectName := range splitFieldPath[:len(splitFieldPath)-1] {
+			subObject, ok := object[objectName].(map[string]interface{})
+			if !ok {
+				if _, exists := object[objectName]; exists {
+					// arrays of objects are flattened by the fields API, so their values can't be added back to each object
+					object = nil
+					break
+				}
+				subObject = make(map[string]interface{})
+				object[objectName] = subObject
+			}
+			object = subObject
+		}
+		if object != nil {
+			object[splitFieldPath[len(splitFieldPath)-1]] = value
+		}
+	}
+}
+
 // extractDocument extracts selected fields from the source data.
 func extractDocument(source map[string]interface{}, selectedFields map[string]types.Field) map[string]interface{} {
 	document := make(map[string]interface{})
@@ -124,8 +199,28 @@ func extractAggregates(aggregates schema.RowSetAggregates, aggregations map[stri
 
 	for aggName, isNested := range postProcessor.ColumnAggregate {
 		if record, ok := aggregations[aggName].(map[string]interface{}); ok {
-			if isNested {
-				record = record[aggName].(map[string]interface{})
+			// the metric of a nested or filtered aggregation is a sub-aggregation with the same name,
+			// which is itself nested when a filtered aggregation is on a nested field
+			for isNested {
+				subRecord, ok := record[aggName].(map[string]interface{})
+				if !ok {
+					break
+				}
+				record = subRecord
+			}
+			switch function := postProcessor.AggregateFunctions[aggName]; function {
+			case "percentiles", "percentile_ranks":
+				aggregates[aggName] = extractPercentiles(record, function)
+				continue
+			case "top_metrics":
+				aggregates[aggName] = extractTopMetrics(record)
+				continue
+			case "geo_bounds":
+				aggregates[aggName] = extractGeoBounds(record)
+				continue
+			case "geo_centroid":
+				aggregates[aggName] = extractGeoCentroid(record)
+				continue
 			}
 			val, ok := record["value"]
 			if ok {
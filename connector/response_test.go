@@ -0,0 +1,186 @@
+package connector
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFetchedFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        map[string]interface{}
+		hitFields     interface{}
+		fetchedFields []string
+		want          map[string]interface{}
+	}{
+		{
+			name:   "single_value_is_unwrapped",
+			source: map[string]interface{}{"hotel": "grand hotel"},
+			hitFields: map[string]interface{}{
+				"channel": []interface{}{"web"},
+			},
+			fetchedFields: []string{"channel"},
+			want:          map[string]interface{}{"hotel": "grand hotel", "channel": "web"},
+		},
+		{
+			name:   "multiple_values_are_kept",
+			source: map[string]interface{}{},
+			hitFields: map[string]interface{}{
+				"tags": []interface{}{"a", "b"},
+			},
+			fetchedFields: []string{"tags"},
+			want:          map[string]interface{}{"tags": []interface{}{"a", "b"}},
+		},
+		{
+			name:          "missing_value_is_null",
+			source:        map[string]interface{}{},
+			hitFields:     nil,
+			fetchedFields: []string{"booked_at"},
+			want:          map[string]interface{}{"booked_at": nil},
+		},
+		{
+			name:   "object_field_is_added_to_object",
+			source: map[string]interface{}{"internal": map[string]interface{}{"note": "late"}},
+			hitFields: map[string]interface{}{
+				"internal.trace_id": []interface{}{"abc"},
+				"meta.size":         []interface{}{float64(3)},
+			},
+			fetchedFields: []string{"internal.trace_id", "meta.size"},
+			want: map[string]interface{}{
+				"internal": map[string]interface{}{"note": "late", "trace_id": "abc"},
+				"meta":     map[string]interface{}{"size": float64(3)},
+			},
+		},
+		{
+			name:   "array_of_objects_is_skipped",
+			source: map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "1"}}},
+			hitFields: map[string]interface{}{
+				"items.size": []interface{}{float64(1)},
+			},
+			fetchedFields: []string{"items.size"},
+			want:          map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": "1"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractFetchedFields(tt.source, tt.hitFields, tt.fetchedFields)
+			assert.Equal(t, tt.want, tt.source)
+		})
+	}
+}
+
+func TestExtractHitWithCollapsed(t *testing.T) {
+	hit := map[string]interface{}{
+		"_id":     "1",
+		"_source": map[string]interface{}{"customer_id": "cust001"},
+		"inner_hits": map[string]interface{}{
+			"_collapsed": map[string]interface{}{
+				"hits": map[string]interface{}{
+					"hits": []interface{}{
+						map[string]interface{}{"_id": "1", "_source": map[string]interface{}{"transaction_id": "t1"}},
+						map[string]interface{}{"_id": "2", "_source": map[string]interface{}{"transaction_id": "t2"}},
+					},
+				},
+			},
+		},
+	}
+	postProcessor := &types.PostProcessor{
+		SelectedFields: map[string]types.Field{
+			"customer_id": {Name: "customer_id"},
+			"members":     {Name: "_collapsed"},
+		},
+		Collapsed: &types.PostProcessor{
+			IsIDSelected: true,
+			SelectedFields: map[string]types.Field{
+				"id":             {Name: "_id"},
+				"transaction_id": {Name: "transaction_id"},
+			},
+		},
+	}
+
+	want := map[string]interface{}{
+		"customer_id": "cust001",
+		"members": []interface{}{
+			map[string]interface{}{"id": "1", "transaction_id": "t1"},
+			map[string]interface{}{"id": "2", "transaction_id": "t2"},
+		},
+	}
+	assert.Equal(t, want, extractHit(hit, postProcessor))
+}
+
+func TestExtractHitWithoutGroup(t *testing.T) {
+	hit := map[string]interface{}{
+		"_id":     "1",
+		"_source": map[string]interface{}{"customer_id": "cust001"},
+	}
+	postProcessor := &types.PostProcessor{
+		IsGroupSelected: true,
+		SelectedFields: map[string]types.Field{
+			"customer_id": {Name: "customer_id"},
+			"group": {
+				Name:   "_group",
+				Fields: map[string]types.Field{"doc_count": {Name: "doc_count"}},
+			},
+		},
+	}
+
+	want := map[string]interface{}{
+		"customer_id": "cust001",
+		"group":       nil,
+	}
+	assert.Equal(t, want, extractHit(hit, postProcessor))
+}
+
+func TestExtractHitWithHighlight(t *testing.T) {
+	hit := map[string]interface{}{
+		"_id":       "1",
+		"_source":   map[string]interface{}{},
+		"highlight": map[string]interface{}{"hotel": []interface{}{"<em>grand</em> hotel"}},
+	}
+	highlight := map[string]interface{}{"hotel": []interface{}{"<em>grand</em> hotel"}}
+
+	postProcessor := &types.PostProcessor{
+		IsHighlightSelected: true,
+		SelectedFields: map[string]types.Field{
+			"highlight": {Name: "_highlight"},
+		},
+	}
+	want := map[string]interface{}{"highlight": []interface{}{highlight}}
+	assert.Equal(t, want, extractHit(hit, postProcessor))
+
+	postProcessor.SelectedFields["highlight"] = types.Field{
+		Name:   "_highlight",
+		Fields: map[string]types.Field{"hotel": {Name: "hotel"}},
+	}
+	assert.Equal(t, want, extractHit(hit, postProcessor))
+}
+
+func TestExtractFilteredAggregates(t *testing.T) {
+	aggregations := `{
+  "errors": {"doc_count": 3},
+  "error_apps": {"doc_count": 3, "error_apps": {"value": 2}},
+  "max_error_price": {"doc_count": 3, "max_error_price": {"doc_count": 5, "max_error_price": {"value": 42.5}}}
+}`
+	postProcessor := &types.PostProcessor{
+		ColumnAggregate: map[string]bool{
+			"errors":          false,
+			"error_apps":      true,
+			"max_error_price": true,
+		},
+	}
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(aggregations), &record))
+
+	want := schema.RowSetAggregates{
+		"errors":          float64(3),
+		"error_apps":      float64(2),
+		"max_error_price": 42.5,
+	}
+	assert.Equal(t, want, extractAggregates(schema.RowSetAggregates{}, record, postProcessor))
+}
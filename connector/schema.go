@@ -2,6 +2,10 @@ package connector
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
 
 	"github.com/hasura/ndc-elasticsearch/internal"
 	"github.com/hasura/ndc-elasticsearch/types"
@@ -20,6 +24,7 @@ type collectionObjects struct {
 	name    string                   // collection / index-level object-type name
 	fields  []map[string]interface{} // top-level fields of the collection
 	objects []map[string]interface{} // nested object types (flattened)
+	isIndex bool                     // whether the collection is an index, rather than a native query definition
 }
 
 // GetSchema returns the schema by parsing the configuration.
@@ -68,12 +73,26 @@ func ParseConfigurationToSchema(configuration *types.Configuration, state *types
 			continue
 		}
 
+		// highlighting reads `_source`, so the `_highlight` column only mirrors the mapped fields, not the runtime fields
+		addHighlightObjectType(&ndcSchema, indexName+"._highlight", properties)
+
+		// suggesters suggest from the indexed fields, so the suggest function only suggests from the mapped fields
+		addSuggestFunction(&ndcSchema, indexName, properties, configuration)
+
+		// runtime fields are exposed as regular columns of the index
+		if runtimeFields := configuration.RuntimeFields[indexName]; len(runtimeFields) != 0 {
+			properties = maps.Clone(properties)
+			for fieldName, runtimeField := range runtimeFields {
+				properties[fieldName] = runtimeField.GetFieldMap()
+			}
+		}
+
 		fields, objects := getScalarTypesAndObjects(properties, state, indexName, "")
-		collected = append(collected, collectionObjects{name: indexName, fields: fields, objects: objects})
+		collected = append(collected, collectionObjects{name: indexName, fields: fields, objects: objects, isIndex: true})
 
 		ndcSchema.Collections = append(ndcSchema.Collections, schema.CollectionInfo{
 			Name:                  indexName,
-			Arguments:             internal.CollectionArgumentsMap,
+			Arguments:             getCollectionArguments(&ndcSchema, indexName, configuration.Scripts),
 			Type:                  indexName,
 			UniquenessConstraints: schema.CollectionInfoUniquenessConstraints{},
 			ForeignKeys:           schema.CollectionInfoForeignKeys{},
@@ -98,13 +117,160 @@ func ParseConfigurationToSchema(configuration *types.Configuration, state *types
 	// independent of Go map iteration order).
 	for _, c := range collected {
 		prepareNdcSchema(&ndcSchema, c.name, c.fields, c.objects)
+
+		// Add the _collapsed field to the object type of an index. It holds the members of the collapsed group of a hit,
+		// returned when the results are collapsed with inner hits (see the `collapse` argument), so it has the type of the index.
+		if c.isIndex {
+			ndcSchema.ObjectTypes[c.name].Fields["_collapsed"] = schema.ObjectField{
+				Type: schema.NewArrayType(schema.NewNamedType(c.name)).Encode(),
+			}
+			// Add the _group field to the object type of an index. It holds the key, document count and aggregates of a group,
+			// when the documents are grouped by the `group_by` argument, and is null otherwise.
+			ndcSchema.ObjectTypes[c.name].Fields["_group"] = schema.ObjectField{
+				Type: schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("group"))).Encode(),
+			}
+		}
 	}
 
+	addOperatorArgumentTypes(&ndcSchema)
+	addPercentilesObjectType(&ndcSchema, configuration.Aggregations)
+	removeUnconfiguredAggregateFunctions(&ndcSchema, configuration.Aggregations)
+
 	return &ndcSchema
 }
 
+// removeUnconfiguredAggregateFunctions removes the aggregate functions which depend on the aggregation settings from the scalar types of the schema,
+// when they are not configured: `percentile_ranks` without percentile rank values, `top_metrics` without the sort of an index,
+// and `weighted_avg` without the weight field of an index.
+func removeUnconfiguredAggregateFunctions(ndcSchema *schema.SchemaResponse, settings *types.AggregationSettings) {
+	unconfiguredFunctions := make(map[string]bool)
+	if settings == nil || len(settings.PercentileRankValues) == 0 {
+		unconfiguredFunctions["percentile_ranks"] = true
+	}
+	if settings == nil || len(settings.TopMetricsSort) == 0 {
+		unconfiguredFunctions["top_metrics"] = true
+	}
+	if settings == nil || len(settings.WeightFields) == 0 {
+		unconfiguredFunctions["weighted_avg"] = true
+	}
+	if len(unconfiguredFunctions) == 0 {
+		return
+	}
+
+	for typeName, scalarType := range ndcSchema.ScalarTypes {
+		// the aggregate functions are shared with the static scalar types, so they are copied before being modified
+		aggregateFunctions := make(schema.ScalarTypeAggregateFunctions, len(scalarType.AggregateFunctions))
+		for function, definition := range scalarType.AggregateFunctions {
+			if !unconfiguredFunctions[function] {
+				aggregateFunctions[function] = definition
+			}
+		}
+		if len(aggregateFunctions) == len(scalarType.AggregateFunctions) {
+			continue
+		}
+		scalarType.AggregateFunctions = aggregateFunctions
+		ndcSchema.ScalarTypes[typeName] = scalarType
+	}
+}
+
+// getCollectionArguments returns the arguments of the collection of an index, which are the common collection arguments
+// and an argument for each script of the index. The argument of a script with params is an object of the typed params,
+// and the argument of a script without params is a boolean.
+func getCollectionArguments(ndcSchema *schema.SchemaResponse, indexName string, scripts map[string]types.Script) schema.CollectionInfoArguments {
+	arguments := maps.Clone(internal.CollectionArgumentsMap)
+
+	for scriptName, script := range scripts {
+		if script.Index != indexName {
+			continue
+		}
+
+		var description *string
+		if script.Description != "" {
+			description = &script.Description
+		}
+
+		if len(script.Params) == 0 {
+			arguments[scriptName] = schema.ArgumentInfo{
+				Description: description,
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			}
+			continue
+		}
+
+		paramsObjectName := scriptName + "_script_params"
+		paramsFields := make(schema.ObjectTypeFields)
+		for paramName, paramType := range script.Params {
+			paramsFields[paramName] = schema.ObjectField{
+				Type: schema.NewNamedType(paramType).Encode(),
+			}
+			if scalarType, ok := internal.ScalarTypeMap[paramType]; ok {
+				ndcSchema.ScalarTypes[paramType] = scalarType
+			}
+		}
+		ndcSchema.ObjectTypes[paramsObjectName] = schema.ObjectType{
+			Fields: paramsFields,
+		}
+
+		arguments[scriptName] = schema.ArgumentInfo{
+			Description: description,
+			Type:        schema.NewNullableNamedType(paramsObjectName).Encode(),
+		}
+	}
+
+	return arguments
+}
+
+// addOperatorArgumentTypes adds the types referenced by the arguments of the comparison operators
+// (e.g. the `<type>_bounds` object types of range fields), and the types of their fields, to the schema.
+func addOperatorArgumentTypes(ndcSchema *schema.SchemaResponse) {
+	pending := make([]string, 0)
+	for _, scalarType := range ndcSchema.ScalarTypes {
+		pending = append(pending, getOperatorArgumentTypeNames(scalarType)...)
+	}
+
+	for len(pending) != 0 {
+		typeName := pending[0]
+		pending = pending[1:]
+
+		if _, ok := ndcSchema.ScalarTypes[typeName]; ok {
+			continue
+		}
+		if _, ok := ndcSchema.ObjectTypes[typeName]; ok {
+			continue
+		}
+
+		if scalarType, ok := internal.ScalarTypeMap[typeName]; ok {
+			ndcSchema.ScalarTypes[typeName] = scalarType
+			pending = append(pending, getOperatorArgumentTypeNames(scalarType)...)
+		} else if objectType, ok := internal.ObjectTypeMap[typeName]; ok {
+			ndcSchema.ObjectTypes[typeName] = objectType
+			for _, field := range objectType.Fields {
+				if namedType := schema.GetUnderlyingNamedType(field.Type); namedType != nil {
+					pending = append(pending, namedType.Name)
+				}
+			}
+		}
+	}
+}
+
+// getOperatorArgumentTypeNames returns the names of the types used by the arguments of the comparison operators of a scalar type.
+func getOperatorArgumentTypeNames(scalarType schema.ScalarType) []string {
+	typeNames := make([]string, 0)
+	for _, operator := range scalarType.ComparisonOperators {
+		customOperator, err := operator.AsCustom()
+		if err != nil {
+			continue
+		}
+		if namedType := schema.GetUnderlyingNamedType(customOperator.ArgumentType); namedType != nil {
+			typeNames = append(typeNames, namedType.Name)
+		}
+	}
+	return typeNames
+}
+
 // parseNativeQueryToSchema parses the given native queries and adds them to the schema response.
-// It also handles return types of kind "defination" and updates the state accordingly.
+// It also handles return types of kind "defination" and updates the state accordingly,
+// and adds the object types of the results of the native queries of kind "aggregations".
 func parseNativeQueryToSchema(schemaResponse *schema.SchemaResponse, state *types.State, nativeQueries map[string]types.NativeQuery, collected *[]collectionObjects) {
 	for queryName, queryConfig := range nativeQueries {
 		indexName := queryConfig.Index
@@ -137,6 +303,18 @@ func parseNativeQueryToSchema(schemaResponse *schema.SchemaResponse, state *type
 			*collected = append(*collected, collectionObjects{name: indexName, fields: fields, objects: objects})
 		}
 
+		uniquenessConstraints := schema.CollectionInfoUniquenessConstraints{
+			queryName + "_by_id": schema.UniquenessConstraint{
+				UniqueColumns: []string{"_id"},
+			},
+		}
+		if returnTypeKind == "aggregations" {
+			// the single row of the aggregations has no _id
+			indexName = queryName
+			addNativeAggregationsObjectType(schemaResponse, queryName, returnType.Aggregations)
+			uniquenessConstraints = schema.CollectionInfoUniquenessConstraints{}
+		}
+
 		// Get arguments for the collection info
 		arguments := schema.CollectionInfoArguments{}
 		if queryConfig.Arguments != nil {
@@ -144,15 +322,11 @@ func parseNativeQueryToSchema(schemaResponse *schema.SchemaResponse, state *type
 		}
 
 		collectionInfo := schema.CollectionInfo{
-			Name:      queryName,
-			Arguments: arguments,
-			Type:      indexName,
-			UniquenessConstraints: schema.CollectionInfoUniquenessConstraints{
-				queryName + "_by_id": schema.UniquenessConstraint{
-					UniqueColumns: []string{"_id"},
-				},
-			},
-			ForeignKeys: schema.CollectionInfoForeignKeys{},
+			Name:                  queryName,
+			Arguments:             arguments,
+			Type:                  indexName,
+			UniquenessConstraints: uniquenessConstraints,
+			ForeignKeys:           schema.CollectionInfoForeignKeys{},
 		}
 
 		schemaResponse.Collections = append(schemaResponse.Collections, collectionInfo)
@@ -197,10 +371,24 @@ func getScalarTypesAndObjects(properties map[string]interface{}, state *types.St
 
 		if fieldType, ok := fieldIsScalar(fieldMap); ok {
 			scalarFieldType := GetFieldType(fieldMap, state, indexName, fieldWithParent)
-			fields = append(fields, map[string]interface{}{
+			field := map[string]interface{}{
 				"name": fieldName,
 				"type": scalarFieldType,
-			})
+			}
+			descriptions := make([]string, 0)
+			if description := getNormalizerDescription(fieldMap); description != "" {
+				descriptions = append(descriptions, description)
+			}
+			if _, ok := internal.ScalarTypeMap[scalarFieldType].AggregateFunctions["cardinality"]; ok && state.Configuration != nil {
+				settings := state.Configuration.Aggregations.GetDistinctCountSettings(indexName)
+				if description := getDistinctCountDescription(settings, fieldWithParent); description != "" {
+					descriptions = append(descriptions, description)
+				}
+			}
+			if len(descriptions) != 0 {
+				field["description"] = strings.Join(descriptions, " ")
+			}
+			fields = append(fields, field)
 
 		} else if nestedObject, ok := fieldMap["properties"].(map[string]interface{}); ok {
 			if fieldType == "nested" {
@@ -232,6 +420,35 @@ func getScalarTypesAndObjects(properties map[string]interface{}, state *types.St
 	return fields, objects
 }
 
+// getNormalizerDescription documents the normalizers of a keyword field and its keyword subfields.
+// Elasticsearch applies the normalizer to the values of term-level queries, so equality on a normalized keyword
+// matches the normalized value (e.g. it is case-insensitive with the `lowercase` filter).
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/normalizer.html
+func getNormalizerDescription(fieldMap map[string]interface{}) string {
+	descriptions := make([]string, 0)
+	if normalizer, ok := fieldMap["normalizer"].(string); ok {
+		descriptions = append(descriptions, fmt.Sprintf("Normalized with the `%s` normalizer: equality and term-level comparisons match the normalized value.", normalizer))
+	}
+
+	subFields, _ := internal.HasSubfields(fieldMap)
+	subFieldNames := make([]string, 0, len(subFields))
+	for subFieldName := range subFields {
+		subFieldNames = append(subFieldNames, subFieldName)
+	}
+	sort.Strings(subFieldNames)
+	for _, subFieldName := range subFieldNames {
+		subFieldMap, ok := subFields[subFieldName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if normalizer, ok := subFieldMap["normalizer"].(string); ok {
+			descriptions = append(descriptions, fmt.Sprintf("The `%s` subfield is normalized with the `%s` normalizer: equality and term-level comparisons on it match the normalized value.", subFieldName, normalizer))
+		}
+	}
+
+	return strings.Join(descriptions, " ")
+}
+
 // prepareNdcSchema prepares the NDC schema. It takes in the NDC schema,
 // the index name, the fields and objects from Elasticsearch mappings,
 // and adds them to the NDC schema.
@@ -244,6 +461,20 @@ func prepareNdcSchema(ndcSchema *schema.SchemaResponse, index string, fields []m
 		Type: schema.NewNamedType("_id").Encode(),
 	}
 
+	// Add the _score field to the schema. It is the relevance score of a hit and is only present in search results.
+	// It is nullable because Elasticsearch does not compute it when results are sorted on a field (unless track_scores is set).
+	collectionFields["_score"] = schema.ObjectField{
+		Type: schema.NewNullableNamedType("_score").Encode(),
+	}
+
+	// Add the _highlight field to the schema. It holds the highlighted fragments of a hit, requested with the `highlight` argument.
+	// Its object type mirrors the highlightable fields of the index, and is only added for indices with such fields.
+	if _, ok := ndcSchema.ObjectTypes[index+"._highlight"]; ok {
+		collectionFields["_highlight"] = schema.ObjectField{
+			Type: schema.NewArrayType(schema.NewNamedType(index + "._highlight")).Encode(),
+		}
+	}
+
 	// Add the object type for the index to the schema.
 	ndcSchema.ObjectTypes[index] = schema.ObjectType{
 		Fields: collectionFields,
@@ -262,6 +493,7 @@ func prepareNdcSchema(ndcSchema *schema.SchemaResponse, index string, fields []m
 
 	// Add the required fields to the schema
 	ndcSchema.ScalarTypes["_id"] = internal.ScalarTypeMap["_id"]
+	ndcSchema.ScalarTypes["_score"] = internal.ScalarTypeMap["_score"]
 
 	// ADd the required scalar type to the schema
 	for scalarTypeName, ScalarType := range internal.RequiredScalarTypes {
@@ -274,6 +506,42 @@ func prepareNdcSchema(ndcSchema *schema.SchemaResponse, index string, fields []m
 	}
 }
 
+// addHighlightObjectType adds the object type of the `_highlight` column, which mirrors the highlightable fields of the given properties:
+// a highlightable field is a nullable array of fragments, and an object or nested field is an object type of its highlightable fields.
+// It returns false if the properties have no highlightable field, in which case no object type is added.
+func addHighlightObjectType(ndcSchema *schema.SchemaResponse, objectName string, properties map[string]interface{}) bool {
+	highlightFields := make(schema.ObjectTypeFields)
+	for fieldName, fieldData := range properties {
+		fieldMap, ok := fieldData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if fieldType, ok := fieldIsScalar(fieldMap); ok {
+			if internal.HighlightFieldTypes[fieldType] {
+				highlightFields[fieldName] = schema.ObjectField{
+					Type: schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("keyword"))).Encode(),
+				}
+			}
+		} else if subProperties, ok := fieldMap["properties"].(map[string]interface{}); ok {
+			subObjectName := objectName + "." + fieldName
+			if addHighlightObjectType(ndcSchema, subObjectName, subProperties) {
+				highlightFields[fieldName] = schema.ObjectField{
+					Type: schema.NewArrayType(schema.NewNamedType(subObjectName)).Encode(),
+				}
+			}
+		}
+	}
+
+	if len(highlightFields) == 0 {
+		return false
+	}
+	ndcSchema.ObjectTypes[objectName] = schema.ObjectType{
+		Fields: highlightFields,
+	}
+	return true
+}
+
 // getNdcObjectFields generates the object fields for the NDC schema
 // based on the Elasticsearch fields.
 func getNdcObjectFields(fields []map[string]interface{}, ndcSchema *schema.SchemaResponse) schema.ObjectTypeFields {
@@ -302,9 +570,13 @@ func getNdcObjectFields(fields []map[string]interface{}, ndcSchema *schema.Schem
 			}
 		} else {
 			// If it is not nested, make it an object in the schema
-			ndcObjectFields[fieldName] = schema.ObjectField{
+			ndcObjectField := schema.ObjectField{
 				Type: schema.NewNamedType(fieldType).Encode(),
 			}
+			if description, ok := field["description"].(string); ok {
+				ndcObjectField.Description = &description
+			}
+			ndcObjectFields[fieldName] = ndcObjectField
 		}
 	}
 
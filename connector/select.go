@@ -40,10 +40,46 @@ func prepareSelectFields(
 		if parentField != "" {
 			column = parentField + "." + columnData.Column
 		} else {
-			// If the column is "_id", update the post processor
+			// If the column is "_id" or "_score", update the post processor
 			if columnData.Column == "_id" {
 				postProcessor.IsIDSelected = true
 			}
+			if columnData.Column == "_score" {
+				postProcessor.IsScoreSelected = true
+			}
+			// The _collapsed column holds the members of the collapsed group of a hit, which are selected in the inner hits of the collapse
+			if columnData.Column == "_collapsed" {
+				postProcessor.Collapsed = &types.PostProcessor{}
+				postProcessor.CollapsedFields = getNestedObjectFields(columnData.Fields)
+				selectedFields[fieldName] = field
+				continue
+			}
+			// The _group column is not part of the document, it is built from the bucket of a group of the `group_by` argument
+			if columnData.Column == "_group" {
+				postProcessor.IsGroupSelected = true
+				if columnData.Fields != nil {
+					_, groupFields, err := prepareNestedSelectField(ctx, columnData.Fields, postProcessor, column)
+					if err != nil {
+						return nil, nil, err
+					}
+					field.Fields = groupFields
+				}
+				selectedFields[fieldName] = field
+				continue
+			}
+			// The _highlight column is not part of the document, it is built from the highlight of the hit
+			if columnData.Column == "_highlight" {
+				postProcessor.IsHighlightSelected = true
+				if columnData.Fields != nil {
+					_, highlightFields, err := prepareNestedSelectField(ctx, columnData.Fields, postProcessor, column)
+					if err != nil {
+						return nil, nil, err
+					}
+					field.Fields = highlightFields
+				}
+				selectedFields[fieldName] = field
+				continue
+			}
 		}
 
 		if columnData.Fields == nil {
@@ -87,3 +123,18 @@ func prepareNestedSelectField(
 		)
 	}
 }
+
+// getNestedObjectFields returns the fields selected in the objects of a nested field.
+func getNestedObjectFields(field schema.NestedField) schema.QueryFields {
+	if field == nil {
+		return nil
+	}
+	switch nestedField := field.Interface().(type) {
+	case *schema.NestedObject:
+		return nestedField.Fields
+	case *schema.NestedArray:
+		return getNestedObjectFields(nestedField.Fields)
+	default:
+		return nil
+	}
+}
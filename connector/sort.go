@@ -27,6 +27,14 @@ func prepareSortElement(element *schema.OrderByElement, state *types.State, coll
 	sort := make(map[string]interface{})
 	switch target := element.Target.Interface().(type) {
 	case *schema.OrderByColumn:
+		// _score is not a field in the mappings, Elasticsearch sorts on it directly.
+		if target.Name == "_score" && len(target.FieldPath) == 0 {
+			sort["_score"] = map[string]interface{}{
+				"order": string(element.OrderDirection),
+			}
+			return sort, nil
+		}
+
 		// Join the field path to get the field path and nested path.
 		fieldPath, nestedPath := joinFieldPath(state, target.FieldPath, target.Name, collection)
 
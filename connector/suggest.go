@@ -0,0 +1,299 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hasura/ndc-elasticsearch/internal"
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// suggestFunctionPrefix is the prefix of the name of the suggest function of an index.
+const suggestFunctionPrefix = "suggest_"
+
+// suggesters are the suggesters run by the suggest functions, in the order of their arguments.
+var suggesters = []string{"completion", "term", "phrase"}
+
+// addSuggestFunction adds the `suggest_<index>` function of an index, which runs the completion, term and phrase suggesters
+// on the fields of the index. The function is only added if the index has a field any suggester can suggest from,
+// and if its name does not clash with the name of a collection.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html
+func addSuggestFunction(ndcSchema *schema.SchemaResponse, indexName string, properties map[string]interface{}, configuration *types.Configuration) {
+	functionName := suggestFunctionPrefix + indexName
+	if _, ok := configuration.Indices[functionName]; ok {
+		return
+	}
+	if _, ok := configuration.Queries[functionName]; ok {
+		return
+	}
+
+	suggestFields := getSuggestFields(properties, "")
+	if len(suggestFields) == 0 {
+		return
+	}
+
+	arguments := schema.FunctionInfoArguments{
+		"text": schema.ArgumentInfo{
+			Description: utils.ToPtr("The text to get suggestions for. It is the prefix of the suggestions of the completion suggester."),
+			Type:        schema.NewNamedType("keyword").Encode(),
+		},
+	}
+	for _, suggester := range suggesters {
+		fields, ok := suggestFields[suggester]
+		if !ok {
+			continue
+		}
+		arguments[suggester] = schema.ArgumentInfo{
+			Description: utils.ToPtr(fmt.Sprintf("Options of the %s suggester. Suggests from one of the fields: %s.", suggester, strings.Join(fields, ", "))),
+			Type:        schema.NewNullableNamedType(suggester + "_suggester").Encode(),
+		}
+	}
+
+	ndcSchema.Functions = append(ndcSchema.Functions, schema.FunctionInfo{
+		Name:        functionName,
+		Description: utils.ToPtr(fmt.Sprintf("Suggests terms, phrases and completions of the text from the documents of the %s index.", indexName)),
+		Arguments:   arguments,
+		ResultType:  schema.NewNamedType("suggest_result").Encode(),
+	})
+
+	for objectName, objectType := range internal.SuggestObjectTypes {
+		ndcSchema.ObjectTypes[objectName] = objectType
+	}
+}
+
+// getSuggestFields returns the sorted paths of the fields (and subfields) of the given properties each suggester can suggest from.
+// The fields of nested documents are skipped, as suggesters don't support them.
+func getSuggestFields(properties map[string]interface{}, parentPath string) map[string][]string {
+	suggestFields := make(map[string][]string)
+	collectSuggestFields(properties, parentPath, suggestFields)
+	for _, fields := range suggestFields {
+		sort.Strings(fields)
+	}
+	return suggestFields
+}
+
+func collectSuggestFields(properties map[string]interface{}, parentPath string, suggestFields map[string][]string) {
+	for fieldName, fieldData := range properties {
+		fieldMap, ok := fieldData.(map[string]interface{})
+		if !ok || fieldMap["type"] == "nested" {
+			continue
+		}
+		fieldPath := fieldName
+		if parentPath != "" {
+			fieldPath = parentPath + "." + fieldName
+		}
+
+		if subProperties, ok := fieldMap["properties"].(map[string]interface{}); ok {
+			collectSuggestFields(subProperties, fieldPath, suggestFields)
+			continue
+		}
+
+		addSuggestField(fieldPath, fieldMap, suggestFields)
+		if subFields, ok := internal.HasSubfields(fieldMap); ok {
+			for subFieldName, subFieldData := range subFields {
+				if subFieldMap, ok := subFieldData.(map[string]interface{}); ok {
+					addSuggestField(fieldPath+"."+subFieldName, subFieldMap, suggestFields)
+				}
+			}
+		}
+	}
+}
+
+func addSuggestField(fieldPath string, fieldMap map[string]interface{}, suggestFields map[string][]string) {
+	fieldType, ok := fieldMap["type"].(string)
+	if !ok {
+		return
+	}
+	for suggester, fieldTypes := range internal.SuggesterFieldTypes {
+		if fieldTypes[fieldType] {
+			suggestFields[suggester] = append(suggestFields[suggester], fieldPath)
+		}
+	}
+}
+
+// getSuggestFunctionIndex returns the index of the suggest function of the given name,
+// if it is one of the suggest functions registered in the schema by addSuggestFunction.
+func getSuggestFunctionIndex(ndcSchema *schema.SchemaResponse, name string) (string, bool) {
+	index, ok := strings.CutPrefix(name, suggestFunctionPrefix)
+	if !ok || ndcSchema == nil {
+		return "", false
+	}
+	for _, function := range ndcSchema.Functions {
+		if function.Name == name {
+			return index, true
+		}
+	}
+	return "", false
+}
+
+// executeSuggestFunction executes a suggest function of an index, with one search per variable set.
+func executeSuggestFunction(ctx context.Context, state *types.State, request *schema.QueryRequest, index string) (schema.QueryResponse, error) {
+	logger := connector.GetLogger(ctx)
+
+	valueFields, err := utils.EvalFunctionSelectionFieldValue(request)
+	if err != nil {
+		return nil, schema.UnprocessableContentError(err.Error(), nil)
+	}
+
+	variableSets := request.Variables
+	if len(variableSets) == 0 {
+		variableSets = []schema.QueryRequestVariablesElem{{}}
+	}
+
+	rowSets := make([]schema.RowSet, 0, len(variableSets))
+	for _, variables := range variableSets {
+		arguments, err := utils.ResolveArgumentVariables(request.Arguments, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		dslQuery, err := prepareSuggestQuery(arguments, state, index)
+		if err != nil {
+			return nil, err
+		}
+
+		searchContext, searchSpan := state.Tracer.Start(ctx, "database_request")
+		queryJson, _ := json.Marshal(dslQuery)
+		setDatabaseAttribute(searchSpan, state, index, string(queryJson))
+		addSpanEvent(searchSpan, logger, "search_elasticsearch", map[string]any{
+			"elasticsearch_request": dslQuery,
+		})
+		res, err := state.Client.Search(searchContext, index, dslQuery)
+		if err != nil {
+			searchSpan.SetStatus(codes.Error, err.Error())
+			searchSpan.End()
+			return nil, schema.UnprocessableContentError("failed to execute query", map[string]any{
+				"error": err.Error(),
+			})
+		}
+		searchSpan.End()
+
+		var result any = extractSuggestions(res)
+		if valueFields != nil {
+			result, err = utils.EvalNestedColumnFields(valueFields, result)
+			if err != nil {
+				return nil, schema.UnprocessableContentError(err.Error(), nil)
+			}
+		}
+
+		rowSets = append(rowSets, schema.RowSet{
+			Aggregates: schema.RowSetAggregates{},
+			Rows: []map[string]any{
+				{"__value": result},
+			},
+		})
+	}
+	return rowSets, nil
+}
+
+// prepareSuggestQuery prepares the search of a suggest function, which runs a suggester for each of the suggester arguments.
+// The text is the prefix of the completion suggester, and the text of the term and phrase suggesters.
+func prepareSuggestQuery(arguments map[string]any, state *types.State, index string) (map[string]interface{}, error) {
+	text, ok := arguments["text"].(string)
+	if !ok {
+		return nil, schema.UnprocessableContentError("text is required to get suggestions", nil)
+	}
+
+	indexMap, err := state.Configuration.GetIndex(index)
+	if err != nil {
+		return nil, schema.UnprocessableContentError(err.Error(), nil)
+	}
+	mappings, _ := indexMap["mappings"].(map[string]interface{})
+	properties, _ := mappings["properties"].(map[string]interface{})
+	suggestFields := getSuggestFields(properties, "")
+
+	suggest := make(map[string]interface{})
+	for _, suggester := range suggesters {
+		options, ok := removeNullOptions(arguments[suggester]).(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		field, _ := options["field"].(string)
+		if !slices.Contains(suggestFields[suggester], field) {
+			return nil, schema.UnprocessableContentError(fmt.Sprintf("the %s suggester can not suggest from the field %s", suggester, field), map[string]any{
+				"index":  index,
+				"fields": suggestFields[suggester],
+			})
+		}
+
+		if suggester == "completion" {
+			suggest[suggester] = map[string]interface{}{
+				"prefix":  text,
+				suggester: options,
+			}
+		} else {
+			suggest[suggester] = map[string]interface{}{
+				"text":    text,
+				suggester: options,
+			}
+		}
+	}
+
+	if len(suggest) == 0 {
+		return nil, schema.UnprocessableContentError("at least one of the completion, term or phrase suggesters is required to get suggestions", nil)
+	}
+
+	return map[string]interface{}{
+		"size":    0,
+		"_source": false,
+		"suggest": suggest,
+	}, nil
+}
+
+// extractSuggestions extracts the suggestions of each suggester from the suggest section of the search response.
+func extractSuggestions(res map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(suggesters))
+	response, _ := res["suggest"].(map[string]interface{})
+	for _, suggester := range suggesters {
+		entries, ok := response[suggester].([]interface{})
+		if !ok {
+			result[suggester] = nil
+			continue
+		}
+
+		suggestions := make([]map[string]interface{}, 0, len(entries))
+		for _, entry := range entries {
+			entryMap, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			options := make([]map[string]interface{}, 0)
+			entryOptions, _ := entryMap["options"].([]interface{})
+			for _, option := range entryOptions {
+				optionMap, ok := option.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				score, ok := optionMap["score"]
+				if !ok {
+					score = optionMap["_score"]
+				}
+				options = append(options, map[string]interface{}{
+					"text":  optionMap["text"],
+					"score": score,
+					"freq":  optionMap["freq"],
+					"_id":   optionMap["_id"],
+				})
+			}
+
+			suggestions = append(suggestions, map[string]interface{}{
+				"text":    entryMap["text"],
+				"offset":  entryMap["offset"],
+				"length":  entryMap["length"],
+				"options": options,
+			})
+		}
+		result[suggester] = suggestions
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package connector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hasura/ndc-elasticsearch/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareSuggestQuery(t *testing.T) {
+	configurationB, err := os.ReadFile(filepath.Join(testsPath, "bookings", "configuration.json"))
+	assert.NoError(t, err)
+	var configuration types.Configuration
+	assert.NoError(t, json.Unmarshal(configurationB, &configuration))
+	state := &types.State{Configuration: &configuration}
+
+	tests := []struct {
+		name      string
+		arguments string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "completion_and_term",
+			arguments: `{
+  "text": "grand h",
+  "completion": {"field": "hotel_suggest", "size": 3, "fuzzy": {"fuzziness": "AUTO", "transpositions": null}, "contexts": {"city": ["paris"]}},
+  "term": {"field": "guest_name", "suggest_mode": "popular"},
+  "phrase": null
+}`,
+			want: `{
+  "size": 0,
+  "_source": false,
+  "suggest": {
+    "completion": {
+      "prefix": "grand h",
+      "completion": {"field": "hotel_suggest", "size": 3, "fuzzy": {"fuzziness": "AUTO"}, "contexts": {"city": ["paris"]}}
+    },
+    "term": {
+      "text": "grand h",
+      "term": {"field": "guest_name", "suggest_mode": "popular"}
+    }
+  }
+}`,
+		},
+		{
+			name:      "phrase",
+			arguments: `{"text": "jon smth", "phrase": {"field": "guest_name", "confidence": 0.5}}`,
+			want: `{
+  "size": 0,
+  "_source": false,
+  "suggest": {
+    "phrase": {
+      "text": "jon smth",
+      "phrase": {"field": "guest_name", "confidence": 0.5}
+    }
+  }
+}`,
+		},
+		{
+			name:      "unsupported_field",
+			arguments: `{"text": "grand", "completion": {"field": "hotel"}}`,
+			wantErr:   true,
+		},
+		{
+			name:      "no_suggester",
+			arguments: `{"text": "grand"}`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var arguments map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(tt.arguments), &arguments))
+
+			got, err := prepareSuggestQuery(arguments, state, "bookings")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			gotJson, err := json.Marshal(got)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(gotJson))
+		})
+	}
+}
+
+func TestGetSuggestFunctionIndex(t *testing.T) {
+	configurationB, err := os.ReadFile(filepath.Join(testsPath, "bookings", "configuration.json"))
+	assert.NoError(t, err)
+	var configuration types.Configuration
+	assert.NoError(t, json.Unmarshal(configurationB, &configuration))
+	ndcSchema := ParseConfigurationToSchema(&configuration, &types.State{
+		SupportedSortFields:      make(map[string]interface{}),
+		SupportedAggregateFields: make(map[string]interface{}),
+		SupportedFilterFields:    make(map[string]interface{}),
+		NestedFields:             make(map[string]interface{}),
+		Configuration:            &configuration,
+	})
+
+	index, ok := getSuggestFunctionIndex(ndcSchema, "suggest_bookings")
+	assert.True(t, ok)
+	assert.Equal(t, "bookings", index)
+
+	for _, name := range []string{"bookings", "suggest_unknown", "suggest_"} {
+		_, ok := getSuggestFunctionIndex(ndcSchema, name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestExtractSuggestions(t *testing.T) {
+	res := `{
+  "suggest": {
+    "completion": [
+      {"text": "grand h", "offset": 0, "length": 7, "options": [{"text": "Grand Hotel", "_id": "1", "_score": 2.0, "_index": "bookings"}]}
+    ],
+    "term": [
+      {"text": "jon", "offset": 0, "length": 3, "options": [{"text": "john", "score": 0.66, "freq": 4}]},
+      {"text": "smith", "offset": 4, "length": 5, "options": []}
+    ]
+  }
+}`
+	want := `{
+  "completion": [
+    {"text": "grand h", "offset": 0, "length": 7, "options": [{"text": "Grand Hotel", "score": 2.0, "freq": null, "_id": "1"}]}
+  ],
+  "term": [
+    {"text": "jon", "offset": 0, "length": 3, "options": [{"text": "john", "score": 0.66, "freq": 4, "_id": null}]},
+    {"text": "smith", "offset": 4, "length": 5, "options": []}
+  ],
+  "phrase": null
+}`
+
+	var resData map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(res), &resData))
+
+	got, err := json.Marshal(extractSuggestions(resData))
+	assert.NoError(t, err)
+	assert.JSONEq(t, want, string(got))
+}
@@ -1,6 +1,8 @@
 package connector
 
 import (
+	"maps"
+
 	"github.com/hasura/ndc-elasticsearch/types"
 	"github.com/hasura/ndc-sdk-go/schema"
 )
@@ -34,6 +36,11 @@ func executeQueryWithVariables(variableSets []schema.QueryRequestVariablesElem,
 
 	topHits := make(map[string]interface{})
 	topHits["_source"] = body["_source"]
+	for _, option := range []string{"fields", "docvalue_fields", "highlight"} {
+		if fields, ok := body[option]; ok {
+			topHits[option] = fields
+		}
+	}
 	topHits["size"] = TOP_HITS_MAX_BUCKET_RESULT_SIZE
 	if size, ok := body["size"]; ok {
 		if (size.(int)) > TOP_HITS_MAX_BUCKET_RESULT_SIZE {
@@ -48,6 +55,9 @@ func executeQueryWithVariables(variableSets []schema.QueryRequestVariablesElem,
 	if sort, ok := body["sort"]; ok {
 		topHits["sort"] = sort
 	}
+	if trackScores, ok := body["track_scores"]; ok {
+		topHits["track_scores"] = trackScores
+	}
 	aggregate := make(map[string]interface{})
 	if aggs, ok := body["aggs"].(map[string]interface{}); ok {
 		aggregate = aggs
@@ -56,6 +66,10 @@ func executeQueryWithVariables(variableSets []schema.QueryRequestVariablesElem,
 		"top_hits": topHits,
 	}
 
+	if runtimeMappings, ok := body["runtime_mappings"]; ok {
+		variableQuery["runtime_mappings"] = runtimeMappings
+	}
+
 	variableQuery["aggs"] = map[string]interface{}{
 		"result": map[string]interface{}{
 			"filters": map[string]interface{}{
@@ -76,6 +90,34 @@ func replaceVariables(input interface{}, variableSet map[string]interface{}) (in
 			return replacement, nil
 		}
 		return nil, schema.UnprocessableContentError("variable not found in variable set", map[string]interface{}{"variable": string(value)})
+	case types.RangeRelationVariable:
+		replacement, ok := variableSet[string(value.Name)]
+		if !ok {
+			return nil, schema.UnprocessableContentError("variable not found in variable set", map[string]interface{}{"variable": string(value.Name)})
+		}
+		bounds, err := processRangeValue(replacement)
+		if err != nil {
+			return nil, err
+		}
+		result := maps.Clone(bounds)
+		result["relation"] = value.Relation
+		return result, nil
+	case types.LikePatternVariable:
+		replacement, ok := variableSet[string(value)]
+		if !ok {
+			return nil, schema.UnprocessableContentError("variable not found in variable set", map[string]interface{}{"variable": string(value)})
+		}
+		return likeToWildcardPattern(replacement)
+	case types.MoreLikeThisVariable:
+		replacement, ok := variableSet[string(value.Name)]
+		if !ok {
+			return nil, schema.UnprocessableContentError("variable not found in variable set", map[string]interface{}{"variable": string(value.Name)})
+		}
+		if replacement == nil {
+			// without more_like_this options, the documents are only filtered by the predicates
+			return map[string]interface{}{"match_all": map[string]interface{}{}}, nil
+		}
+		return buildMoreLikeThisQuery(replacement, value.Index)
 	case []interface{}:
 		result := make([]interface{}, len(value))
 		for i, elem := range value {
@@ -114,3 +156,39 @@ func replaceVariables(input interface{}, variableSet map[string]interface{}) (in
 		return input, nil
 	}
 }
+
+// checkAggregationFilterVariables returns an error if the filter of an aggregation, set by the given argument, has a variable.
+// The aggregations are shared by the variable sets of a query with variables, so their filters can't depend on a variable set.
+func checkAggregationFilterVariables(filter interface{}, argument string, details map[string]any) error {
+	if containsVariable(filter) {
+		return schema.UnprocessableContentError("variables are not supported in "+argument, details)
+	}
+	return nil
+}
+
+// containsVariable checks if the given query has a variable, which is replaced by replaceVariables.
+func containsVariable(input interface{}) bool {
+	switch value := input.(type) {
+	case types.Variable, types.RangeRelationVariable, types.LikePatternVariable, types.MoreLikeThisVariable:
+		return true
+	case []interface{}:
+		for _, elem := range value {
+			if containsVariable(elem) {
+				return true
+			}
+		}
+	case []map[string]interface{}:
+		for _, elem := range value {
+			if containsVariable(elem) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, elem := range value {
+			if containsVariable(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
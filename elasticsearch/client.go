@@ -468,6 +468,35 @@ func (e *Client) GetMappings(ctx context.Context, indices []string) (mappings ma
 
 }
 
+// PutScript creates or updates a stored script.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/create-stored-script-api.html
+func (e *Client) PutScript(ctx context.Context, id string, lang string, source string) error {
+	var buf bytes.Buffer
+	script := map[string]interface{}{
+		"script": map[string]interface{}{
+			"lang":   lang,
+			"source": source,
+		},
+	}
+	if err := json.NewEncoder(&buf).Encode(script); err != nil {
+		return err
+	}
+
+	req := esapi.PutScriptRequest{
+		ScriptID: id,
+		Body:     &buf,
+	}
+
+	// Perform the request
+	res, err := req.Do(ctx, e.getClient())
+	if err != nil {
+		return fmt.Errorf("error storing script %s: %s", id, err)
+	}
+
+	_, err = parseResponse(ctx, res)
+	return err
+}
+
 // parseResponse parses the response from esapi and handles errors.
 func parseResponse(ctx context.Context, res *esapi.Response) (interface{}, error) {
 	logger := connector.GetLogger(ctx)
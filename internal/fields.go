@@ -89,12 +89,20 @@ func IsFieldDtaEnabled(fieldMap map[string]interface{}) bool {
 
 // GetBestFieldOrSubFieldForAggregation returns the best field or subfield for the given aggregation operator
 func GetBestFieldOrSubFieldForAggregation(fieldPath, fieldType string, subFieldMap map[string]string, operator string) (bestFieldOrSubField string, operatorFound bool) {
+	if fieldType == "geo_point" {
+		// geo_point fields are never routed to a subfield, as the geo aggregations are only meaningful on geo points
+		return fieldPath, GeoPointAggregations[operator]
+	}
 	// call the getBestFieldOrSubFieldForOperators function with the aggregation operators
 	return getBestFieldOrSubFieldForOperators(fieldPath, fieldType, subFieldMap, operator, NumericalAggregations, TermLevelAggregations, FullTextAggregations)
 }
 
 // GetBestFieldOrSubFieldForQuery returns the best field or subfield for the given query operator
 func GetBestFieldOrSubFieldForQuery(fieldPath, fieldType string, subFieldMap map[string]string, operator string) (bestFieldOrSubField string, operatorFound bool) {
+	if fieldType == "ip" {
+		// ip fields are never routed to a subfield, as ip comparisons are not meaningful on text or keyword
+		return fieldPath, IpQueries[operator]
+	}
 	// call the getBestFieldOrSubFieldForOperators function with the query operators
 	return getBestFieldOrSubFieldForOperators(fieldPath, fieldType, subFieldMap, operator, NumericalQueries, TermLevelQueries, FullTextQueries)
 }
@@ -9,16 +9,16 @@ import (
 
 var NumericFields = []string{"integer", "long", "short", "byte", "halft_float", "unsigned_long", "float", "double", "scaled_float"}
 
-var ValidFunctions = []string{"sum", "min", "max", "avg", "value_count", "cardinality", "stats", "string_stats"}
+var ValidFunctions = []string{"sum", "min", "max", "avg", "value_count", "cardinality", "stats", "string_stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg", "geo_bounds", "geo_centroid"}
 
 var ScalarTypeMap = map[string]schema.ScalarType{
 	"integer": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "integer"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "integer"),
 		ComparisonOperators: getComparisonOperatorDefinition("integer"),
 		Representation:      schema.NewTypeRepresentationInt32().Encode(),
 	},
 	"long": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "long"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "long"),
 		ComparisonOperators: getComparisonOperatorDefinition("long"),
 		Representation:      schema.NewTypeRepresentationInt64().Encode(),
 	},
@@ -29,21 +29,28 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 	},
 	"_id": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: getComparisonOperatorDefinition("_id"),
+		ComparisonOperators: getIdComparisonOperatorDefinition(),
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
+	"_score": {
+		// `_score` is the relevance score computed by Elasticsearch for a hit.
+		// It only exists in search results, so it can't be filtered on or aggregated.
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationFloat64().Encode(),
+	},
 	"keyword": {
 		AggregateFunctions:  getAggregationFunctions([]string{"value_count", "cardinality", "string_stats"}, "keyword"),
 		ComparisonOperators: getComparisonOperatorDefinition("keyword"),
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"date": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "long"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks"}, "long"),
 		ComparisonOperators: getComparisonOperatorDefinition("date"),
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"half_float": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "half_float"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "half_float"),
 		ComparisonOperators: getComparisonOperatorDefinition("half_float"),
 
 		// `half_float` is a 16-bit floating point number in [Elasticsearch Scalars](1),
@@ -55,7 +62,7 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 		Representation: schema.NewTypeRepresentationFloat32().Encode(),
 	},
 	"byte": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "byte"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "byte"),
 		ComparisonOperators: getComparisonOperatorDefinition("byte"),
 		Representation:      schema.NewTypeRepresentationInt8().Encode(),
 	},
@@ -80,29 +87,29 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"short": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "short"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "short"),
 		ComparisonOperators: getComparisonOperatorDefinition("short"),
 		Representation:      schema.NewTypeRepresentationInt16().Encode(),
 	},
 	"unsigned_long": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "unsigned_long"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "unsigned_long"),
 		ComparisonOperators: getComparisonOperatorDefinition("unsigned_long"),
 		Representation:      schema.NewTypeRepresentationBigInteger().Encode(),
 	},
 	"float": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "float"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "float"),
 		ComparisonOperators: getComparisonOperatorDefinition("float"),
 		Representation:      schema.NewTypeRepresentationFloat32().Encode(),
 	},
 	"double": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "double"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "double"),
 		ComparisonOperators: getComparisonOperatorDefinition("double"),
 		Representation:      schema.NewTypeRepresentationFloat64().Encode(),
 	},
 	"scaled_float": {
 		// A floating point number that is backed by a long, scaled by a fixed double scaling factor.
 		// https://www.elastic.co/guide/en/elasticsearch/reference/current/number.html
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "scaled_float"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "scaled_float"),
 		ComparisonOperators: getComparisonOperatorDefinition("scaled_float"),
 		Representation:      schema.NewTypeRepresentationFloat64().Encode(),
 	},
@@ -112,13 +119,13 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"date_nanos": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "long"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks"}, "long"),
 		ComparisonOperators: getComparisonOperatorDefinition("date_nanos"),
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"ip": {
 		AggregateFunctions:  getAggregationFunctions([]string{"value_count", "cardinality"}, "ip"),
-		ComparisonOperators: getComparisonOperatorDefinition("ip"),
+		ComparisonOperators: getIpComparisonOperatorDefinition(),
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"version": {
@@ -137,7 +144,7 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 		Representation:      schema.NewTypeRepresentationString().Encode(),
 	},
 	"token_count": {
-		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats"}, "integer"),
+		AggregateFunctions:  getAggregationFunctions([]string{"max", "min", "sum", "avg", "value_count", "cardinality", "stats", "percentiles", "percentile_ranks", "median_absolute_deviation", "extended_stats", "top_metrics", "weighted_avg"}, "integer"),
 		ComparisonOperators: getComparisonOperatorDefinition("token_count"),
 		Representation:      schema.NewTypeRepresentationInteger().Encode(),
 	},
@@ -147,77 +154,85 @@ var ScalarTypeMap = map[string]schema.ScalarType{
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
 
-	// ********** NOTE: BEGIN OBJECT TYPES AS JSON SCALARS ***********
-	// the following types are object types, but,
-	// 1. either their internal structure is flexible, or,
-	// 2. they are not fully supported in the connector yet.
-	// therefore, they are added as json scalar types, to atleast allow them to be queryable, albeit without any operators.
-	"sparse_vector": {
+	// ********** NOTE: BEGIN RANGE TYPES ***********
+	// the values of range types are objects with the bounds of the range (gt, gte, lt, lte),
+	// they are queried with range queries using the `relation` parameter, or with a term query for a single value.
+	// They are scalar types rather than object types, since only scalar columns have comparison operators:
+	// the bounds are typed in the arguments of the operators (see getRangeBoundsObjectType).
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/range.html
+	"integer_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("integer_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"dense_vector": {
+	"float_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("float_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"rank_feature": {
+	"long_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("long_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"rank_features": {
+	"double_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("double_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"percolator": {
+	"date_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("date_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"geo_point": {
+	"ip_range": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
-		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		ComparisonOperators: getRangeFieldComparisonOperatorDefinition("ip_range"),
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"geo_shape": {
+	// ********** NOTE: END RANGE TYPES ***********
+
+	// ********** NOTE: BEGIN OBJECT TYPES AS JSON SCALARS ***********
+	// the following types are object types, but,
+	// 1. either their internal structure is flexible, or,
+	// 2. they are not fully supported in the connector yet.
+	// therefore, they are added as json scalar types, to atleast allow them to be queryable, albeit without any operators.
+	"sparse_vector": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"join": {
+	"dense_vector": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"integer_range": {
+	"rank_feature": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"float_range": {
+	"rank_features": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"long_range": {
+	"percolator": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"double_range": {
-		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+	"geo_point": {
+		AggregateFunctions:  getAggregationFunctions([]string{"geo_bounds", "geo_centroid"}, "geo_point"),
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"date_range": {
+	"geo_shape": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
 	},
-	"ip_range": {
+	"join": {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationJSON().Encode(),
@@ -281,6 +296,7 @@ var RequiredScalarTypes = map[string]schema.ScalarType{
 	"keyword": ScalarTypeMap["keyword"],
 	"long":    ScalarTypeMap["long"],
 	"json":    ScalarTypeMap["json"],
+	"boolean": ScalarTypeMap["boolean"],
 }
 
 var RequiredObjectTypes = map[string]schema.ObjectType{
@@ -303,6 +319,129 @@ var RequiredObjectTypes = map[string]schema.ObjectType{
 			},
 		},
 	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-extendedstats-aggregation.html
+	"extended_stats": {
+		Fields: schema.ObjectTypeFields{
+			"count": schema.ObjectField{
+				Type: schema.NewNamedType("integer").Encode(),
+			},
+			"min": schema.ObjectField{
+				Description: utils.ToPtr("The lowest value, null if there are no values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"max": schema.ObjectField{
+				Description: utils.ToPtr("The highest value, null if there are no values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"avg": schema.ObjectField{
+				Description: utils.ToPtr("The average of the values, null if there are no values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"sum": schema.ObjectField{
+				Description: utils.ToPtr("The sum of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"sum_of_squares": schema.ObjectField{
+				Description: utils.ToPtr("The sum of the squares of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"variance": schema.ObjectField{
+				Description: utils.ToPtr("The population variance of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"variance_population": schema.ObjectField{
+				Description: utils.ToPtr("The population variance of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"variance_sampling": schema.ObjectField{
+				Description: utils.ToPtr("The sample variance of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"std_deviation": schema.ObjectField{
+				Description: utils.ToPtr("The population standard deviation of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"std_deviation_population": schema.ObjectField{
+				Description: utils.ToPtr("The population standard deviation of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"std_deviation_sampling": schema.ObjectField{
+				Description: utils.ToPtr("The sample standard deviation of the values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"std_deviation_bounds": schema.ObjectField{
+				Description: utils.ToPtr("The bounds of the values within two standard deviations of the average."),
+				Type:        schema.NewNullableNamedType("extended_stats_bounds").Encode(),
+			},
+		},
+	},
+	"extended_stats_bounds": {
+		Fields: schema.ObjectTypeFields{
+			"upper": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+			"lower": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+			"upper_population": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+			"lower_population": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+			"upper_sampling": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+			"lower_sampling": schema.ObjectField{
+				Type: schema.NewNullableNamedType("double").Encode(),
+			},
+		},
+	},
+	// The fields of the `percentiles` object type depend on the configured percents (see addPercentilesObjectType).
+	"percentile_rank": {
+		Fields: schema.ObjectTypeFields{
+			"value": schema.ObjectField{
+				Description: utils.ToPtr("A configured value."),
+				Type:        schema.NewNamedType("double").Encode(),
+			},
+			"percent": schema.ObjectField{
+				Description: utils.ToPtr("The percentage of values which are lower than or equal to the value, null if there are no values."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+		},
+	},
+	// https://datatracker.ietf.org/doc/html/rfc7946#section-3.1.2
+	"geojson_point": {
+		Description: utils.ToPtr("A GeoJSON Point geometry."),
+		Fields: schema.ObjectTypeFields{
+			"type": schema.ObjectField{
+				Description: utils.ToPtr("The type of the geometry: `Point`."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"coordinates": schema.ObjectField{
+				Description: utils.ToPtr("The longitude and the latitude of the point."),
+				Type:        schema.NewArrayType(schema.NewNamedType("double")).Encode(),
+			},
+		},
+	},
+	// https://datatracker.ietf.org/doc/html/rfc7946#section-3.1.6
+	"geojson_polygon": {
+		Description: utils.ToPtr("A GeoJSON Polygon geometry of a bounding box, with its bounding box."),
+		Fields: schema.ObjectTypeFields{
+			"type": schema.ObjectField{
+				Description: utils.ToPtr("The type of the geometry: `Polygon`."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"bbox": schema.ObjectField{
+				Description: utils.ToPtr("The west, south, east and north bounds of the polygon. The west bound is greater than the east bound when the polygon crosses the antimeridian."),
+				Type:        schema.NewArrayType(schema.NewNamedType("double")).Encode(),
+			},
+			"coordinates": schema.ObjectField{
+				Description: utils.ToPtr("The linear ring of the polygon, as [longitude, latitude] positions."),
+				Type:        schema.NewArrayType(schema.NewArrayType(schema.NewArrayType(schema.NewNamedType("double")))).Encode(),
+			},
+		},
+	},
 	"string_stats": {
 		Fields: schema.ObjectTypeFields{
 			"count": schema.ObjectField{
@@ -346,6 +485,405 @@ var RequiredObjectTypes = map[string]schema.ObjectType{
 			},
 		},
 	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/collapse-search-results.html
+	"collapse_options": {
+		Fields: schema.ObjectTypeFields{
+			"field": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Field to collapse the results on. Must be a single-valued keyword or numeric field."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"inner_hits": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns the top members of each group in the '_collapsed' column."),
+				Type:        schema.NewNullableNamedType("collapse_inner_hits").Encode(),
+			},
+			"max_concurrent_group_searches": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Number of concurrent requests allowed to retrieve the inner hits per group."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+		},
+	},
+	"collapse_inner_hits": {
+		Fields: schema.ObjectTypeFields{
+			"size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Maximum number of members to return per group. Defaults to 3."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"from": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Offset of the first member to return per group. Defaults to 0."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-composite-aggregation.html
+	"group_by_options": {
+		Fields: schema.ObjectTypeFields{
+			"dimensions": schema.ObjectField{
+				Description: utils.ToPtr("(Required) The dimensions to group the documents by, in order."),
+				Type:        schema.NewArrayType(schema.NewNamedType("group_dimension")).Encode(),
+			},
+			"mode": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) `composite` to return all the groups, page by page, `terms` to return the top groups of each dimension, or `significant_terms` and `rare_terms` to return the significant or rare terms of the last dimension, which must be a keyword column. Defaults to `composite`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"after": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, object) The key of the last group of the previous page, to return the next page of groups (`composite` mode only)."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"aggregates": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) The aggregates to compute for each group, returned in the 'aggregates' of the '_group' column."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("group_aggregate"))).Encode(),
+			},
+			"order_by": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) The order of the groups (`terms` mode only). Defaults to the descending document count."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("group_order"))).Encode(),
+			},
+			"background_predicate": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, object) The predicate of the background set of documents the significant terms are compared to, in the format of the predicates of NDC queries (`significant_terms` mode only). Defaults to all the documents of the index."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"max_doc_count": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The maximum number of documents of a rare term (`rare_terms` mode only). Defaults to 1."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+		},
+	},
+	"group_dimension": {
+		Fields: schema.ObjectTypeFields{
+			"column": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The column to group by. The keyword subfield of a text field is used."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"order": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The order of the values of the dimension: `asc` or `desc` (`composite` mode only). Defaults to `asc`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"missing_bucket": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether the documents without a value for the column are grouped with a null key (`composite` mode only). Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"date_histogram": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a date column by calendar or fixed intervals, instead of by value."),
+				Type:        schema.NewNullableNamedType("date_histogram_options").Encode(),
+			},
+			"histogram": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a numeric column by fixed-width intervals, instead of by value."),
+				Type:        schema.NewNullableNamedType("histogram_options").Encode(),
+			},
+			"ranges": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a numeric, date or ip column by ranges, instead of by value (`terms` mode only). The bounds of the ranges are returned in the `ranges` of the group."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("range_bucket"))).Encode(),
+			},
+			"geohash_grid": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a geo_point column by the cells of a geohash grid (`terms` mode only). The cells are returned in the `cells` of the group."),
+				Type:        schema.NewNullableNamedType("geo_grid_options").Encode(),
+			},
+			"geotile_grid": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a geo_point column by the cells of a map tile grid. The cells are returned in the `cells` of the group."),
+				Type:        schema.NewNullableNamedType("geo_grid_options").Encode(),
+			},
+			"geohex_grid": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Groups the values of a geo_point column by the cells of an H3 hexagonal grid (`terms` mode only). The cells are returned in the `cells` of the group."),
+				Type:        schema.NewNullableNamedType("geo_grid_options").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-datehistogram-aggregation.html
+	"date_histogram_options": {
+		Fields: schema.ObjectTypeFields{
+			"calendar_interval": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) A calendar interval, e.g. `1d`, `1w`, `1M`, `1q` or `1y`. Either 'calendar_interval' or 'fixed_interval' is required."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"fixed_interval": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) A fixed interval, e.g. `30s`, `15m`, `12h` or `2d`. Either 'calendar_interval' or 'fixed_interval' is required."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"time_zone": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The time zone of the intervals and keys, as an ISO 8601 UTC offset (e.g. `+01:00`) or an IANA time zone ID (e.g. `Europe/Paris`). Defaults to UTC."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"offset": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Shifts the start of each interval by a duration, e.g. `+6h` or `-1d`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"min_doc_count": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The minimum number of documents of an interval to return it (`terms` mode only). Defaults to 0, which returns the empty intervals between the first and the last interval."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"extended_bounds": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns the empty intervals from 'min' to 'max', beyond the dates of the documents (`terms` mode only)."),
+				Type:        schema.NewNullableNamedType("date_histogram_bounds").Encode(),
+			},
+		},
+	},
+	"date_histogram_bounds": {
+		Fields: schema.ObjectTypeFields{
+			"min": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, date) The start of the first interval."),
+				Type:        schema.NewNullableNamedType("date").Encode(),
+			},
+			"max": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, date) The start of the last interval."),
+				Type:        schema.NewNullableNamedType("date").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-histogram-aggregation.html
+	"histogram_options": {
+		Fields: schema.ObjectTypeFields{
+			"interval": schema.ObjectField{
+				Description: utils.ToPtr("(Required, double) The width of the intervals."),
+				Type:        schema.NewNamedType("double").Encode(),
+			},
+			"offset": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, double) Shifts the start of each interval, between 0 and 'interval' (`terms` mode only). Defaults to 0."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"min_doc_count": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The minimum number of documents of an interval to return it (`terms` mode only). Defaults to 0, which returns the empty intervals between the first and the last interval."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"extended_bounds": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns the empty intervals from 'min' to 'max', beyond the values of the documents (`terms` mode only)."),
+				Type:        schema.NewNullableNamedType("histogram_bounds").Encode(),
+			},
+		},
+	},
+	"histogram_bounds": {
+		Fields: schema.ObjectTypeFields{
+			"min": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, double) The start of the first interval."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"max": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, double) The start of the last interval."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-geohashgrid-aggregation.html
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-geotilegrid-aggregation.html
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-geohexgrid-aggregation.html
+	"geo_grid_options": {
+		Fields: schema.ObjectTypeFields{
+			"precision": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The precision of the grid: the length of the geohashes (1 to 12, defaults to 5), the zoom level of the map tiles (0 to 29, defaults to 7), or the resolution of the H3 cells (0 to 15, defaults to 6)."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-cardinality-aggregation.html
+	"distinct_count_options": {
+		Fields: schema.ObjectTypeFields{
+			"precision_threshold": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The count below which the approximate distinct counts are expected to be close to accurate, at most 40000. Overrides the precision threshold of the index."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"exact": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether the distinct values are counted exactly, by paginating a composite aggregation, or approximately, with a cardinality aggregation. Overrides the mode of the columns of the index. Exact counts are limited to low-to-medium cardinalities, and are not supported on nested columns, with aggregate predicates or with variables."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-range-aggregation.html
+	"range_bucket": {
+		Fields: schema.ObjectTypeFields{
+			"key": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The key of the range. Defaults to the bounds of the range, e.g. `100.0-200.0`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"from": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) The start of the range, included: a number, a date (or date math expression, e.g. `now-1M/M`) or an IP address, depending on the column. Unbounded if not set."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"to": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) The end of the range, excluded. Unbounded if not set."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"mask": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The IP range as a CIDR mask, e.g. `10.0.0.0/25`, instead of 'from' and 'to' (ip columns only)."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	"group_aggregate": {
+		Fields: schema.ObjectTypeFields{
+			"name": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The name of the aggregate in the 'aggregates' of the '_group' column."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"function": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) `count`, or an aggregate function of the type of the column, e.g. `sum`, `avg`, `max` or `cardinality`."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"column": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The column to aggregate. Without a column, `count` counts the documents of the group."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"distinct": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether `count` only counts the distinct values of the column. Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"predicate": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) A predicate in the format of the predicates of NDC queries. The aggregate is only computed on the documents of the group which match the predicate."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+		},
+	},
+	"group_order": {
+		Fields: schema.ObjectTypeFields{
+			"by": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) `_count` to order by document count, `_key` to order by the value of the dimension, or the name of a single value aggregate of the groups."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"direction": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) `asc` or `desc`. Defaults to `desc`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	"group": {
+		Fields: schema.ObjectTypeFields{
+			"key": schema.ObjectField{
+				Description: utils.ToPtr("The values of the dimensions of the group, by column. In `composite` mode, it can be passed as 'after' to get the groups after this one. Null when the documents are not grouped."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"doc_count": schema.ObjectField{
+				Description: utils.ToPtr("The number of documents in the group. Null when the documents are not grouped."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"aggregates": schema.ObjectField{
+				Description: utils.ToPtr("The aggregates of the group, by name."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+			"ranges": schema.ObjectField{
+				Description: utils.ToPtr("The ranges of the group, for its dimensions grouped by ranges. Null when no dimension is grouped by ranges."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("group_range"))).Encode(),
+			},
+			"score": schema.ObjectField{
+				Description: utils.ToPtr("The significance score of the term of the last dimension (`significant_terms` mode only)."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"bg_count": schema.ObjectField{
+				Description: utils.ToPtr("The number of documents of the background set with the term of the last dimension (`significant_terms` mode only)."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"cells": schema.ObjectField{
+				Description: utils.ToPtr("The grid cells of the group, for its dimensions grouped by geo grid cells. Null when no dimension is grouped by geo grid cells."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("group_cell"))).Encode(),
+			},
+		},
+	},
+	"group_cell": {
+		Fields: schema.ObjectTypeFields{
+			"column": schema.ObjectField{
+				Description: utils.ToPtr("The column of the dimension grouped by the cell."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"grid": schema.ObjectField{
+				Description: utils.ToPtr("The grid of the cell: `geohash_grid`, `geotile_grid` or `geohex_grid`."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"key": schema.ObjectField{
+				Description: utils.ToPtr("The key of the cell: a geohash, a `zoom/x/y` map tile, or an H3 cell index."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"bounds": schema.ObjectField{
+				Description: utils.ToPtr("The bounding box of the cell. Null for H3 cells."),
+				Type:        schema.NewNullableNamedType("geojson_polygon").Encode(),
+			},
+			"centroid": schema.ObjectField{
+				Description: utils.ToPtr("The centroid of the locations of the documents of the group in the cell."),
+				Type:        schema.NewNullableNamedType("geojson_point").Encode(),
+			},
+		},
+	},
+	"group_range": {
+		Fields: schema.ObjectTypeFields{
+			"column": schema.ObjectField{
+				Description: utils.ToPtr("The column of the dimension grouped by the range."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"key": schema.ObjectField{
+				Description: utils.ToPtr("The key of the range."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"from": schema.ObjectField{
+				Description: utils.ToPtr("The start of the range, as a number (epoch milliseconds for dates). Null when unbounded, and for IP ranges."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"to": schema.ObjectField{
+				Description: utils.ToPtr("The end of the range, as a number (epoch milliseconds for dates). Null when unbounded, and for IP ranges."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"from_as_string": schema.ObjectField{
+				Description: utils.ToPtr("The start of the range, for date and IP ranges. Null when unbounded."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"to_as_string": schema.ObjectField{
+				Description: utils.ToPtr("The end of the range, for date and IP ranges. Null when unbounded."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-mlt-query.html
+	"more_like_this_options": {
+		Fields: schema.ObjectTypeFields{
+			"ids": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, array of strings) The '_id' values of the documents of the collection to find similar documents to. Either 'ids' or 'text' is required."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("_id"))).Encode(),
+			},
+			"text": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The free text to find similar documents to. Either 'ids' or 'text' is required."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"fields": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, array of strings) The text or keyword fields to compare the documents on. Defaults to the 'index.query.default_field' index setting."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("keyword"))).Encode(),
+			},
+			"min_term_freq": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The minimum frequency of a term in the input documents for it to be selected. Defaults to 2."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"max_query_terms": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The maximum number of terms selected from the input documents. Defaults to 25."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"minimum_should_match": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) The number or percentage of the selected terms a document must match, e.g. '3' or '30%'. Defaults to '30%'."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/highlighting.html
+	"highlight_options": {
+		Fields: schema.ObjectTypeFields{
+			"fields": schema.ObjectField{
+				Description: utils.ToPtr("(Required, array of strings) Fields to highlight. Wildcards are supported, e.g. `address.*`."),
+				Type:        schema.NewArrayType(schema.NewNamedType("keyword")).Encode(),
+			},
+			"fragment_size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Size of the highlighted fragments in characters. Defaults to 100."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"number_of_fragments": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Maximum number of fragments to return. If 0, the whole field is highlighted. Defaults to 5."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"pre_tags": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, array of strings) Tags inserted before the highlighted text. Defaults to `<em>`."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("keyword"))).Encode(),
+			},
+			"post_tags": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, array of strings) Tags inserted after the highlighted text. Defaults to `</em>`."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("keyword"))).Encode(),
+			},
+			"type": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Highlighter to use: `unified`, `plain` or `fvh`. Defaults to `unified`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
 }
 
 var ObjectTypeMap = map[string]schema.ObjectType{
@@ -355,6 +893,15 @@ var ObjectTypeMap = map[string]schema.ObjectType{
 			"counts": schema.ObjectField{Type: schema.NewNamedType("integer").Encode()},
 		},
 	},
+	// the arguments of the range relation operators of the range field types
+	"integer_range_bounds": getRangeBoundsObjectType("integer"),
+	"float_range_bounds":   getRangeBoundsObjectType("float"),
+	"long_range_bounds":    getRangeBoundsObjectType("long"),
+	"double_range_bounds":  getRangeBoundsObjectType("double"),
+	"date_range_bounds":    getRangeBoundsObjectType("date"),
+	"ip_range_bounds":      getRangeBoundsObjectType("ip"),
+	// the argument of the `range` operator of ip fields
+	"ip_range_query": getRangeBoundsObjectType("ip"),
 	"date_range_query": {
 		Fields: schema.ObjectTypeFields{
 			"gt": schema.ObjectField{
@@ -387,10 +934,408 @@ var ObjectTypeMap = map[string]schema.ObjectType{
 			},
 		},
 	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-fuzzy-query.html
+	"fuzzy_query": {
+		Fields: schema.ObjectTypeFields{
+			"value": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Term you wish to find in the provided field."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"fuzziness": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Maximum edit distance allowed for matching, e.g. `AUTO`, `0`, `1` or `2`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"max_expansions": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Maximum number of variations created. Defaults to 50."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"prefix_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Number of beginning characters left unchanged when creating expansions. Defaults to 0."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"transpositions": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Indicates whether edits include transpositions of two adjacent characters (ab → ba). Defaults to true."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"rewrite": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Method used to rewrite the query."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-terms-set-query.html
+	"terms_set_query": {
+		Fields: schema.ObjectTypeFields{
+			"terms": schema.ObjectField{
+				Description: utils.ToPtr("(Required, array) Array of terms you wish to find in the provided field."),
+				Type:        schema.NewArrayType(schema.NewNamedType("keyword")).Encode(),
+			},
+			"minimum_should_match_field": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Numeric field containing the number of matching terms required to return a document."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"minimum_should_match_script": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, script) Custom script containing the number of matching terms required to return a document."),
+				Type:        schema.NewNullableNamedType("terms_set_script").Encode(),
+			},
+		},
+	},
+	// script used by the `minimum_should_match_script` parameter of the terms_set query
+	"terms_set_script": {
+		Fields: schema.ObjectTypeFields{
+			"source": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Script source. The number of terms is available as `params.num_terms`."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"params": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, object) Parameters passed to the script."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-match-query.html
+	"match_options": {
+		Fields: schema.ObjectTypeFields{
+			"query": schema.ObjectField{
+				Description: utils.ToPtr("(Required) Text, number, boolean value or date you wish to find in the provided field."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"operator": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Boolean logic used to interpret text in the query value. Valid values are `OR` (default) and `AND`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"minimum_should_match": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Minimum number of clauses that must match for a document to be returned."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"analyzer": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Analyzer used to analyze the terms in the query. Defaults to the top-level field's analyzer."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"fuzziness": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Maximum edit distance allowed for matching, e.g. `AUTO`, `0`, `1` or `2`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"prefix_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Number of beginning characters left unchanged when creating expansions. Defaults to 0."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"fuzzy_transpositions": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) If true, edits for fuzzy matching include transpositions of two adjacent characters (ab → ba). Defaults to true."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html
+	// exactly one of the rules should be provided
+	"intervals_query": {
+		Fields: schema.ObjectTypeFields{
+			"match": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Matches analyzed text."),
+				Type:        schema.NewNullableNamedType("intervals_match").Encode(),
+			},
+			"prefix": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Matches terms that start with a specified set of characters."),
+				Type:        schema.NewNullableNamedType("intervals_prefix").Encode(),
+			},
+			"wildcard": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Matches terms using a wildcard pattern."),
+				Type:        schema.NewNullableNamedType("intervals_wildcard").Encode(),
+			},
+			"fuzzy": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Matches terms that are similar to the provided term, within an edit distance."),
+				Type:        schema.NewNullableNamedType("intervals_fuzzy").Encode(),
+			},
+			"all_of": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns matches that span a combination of other rules."),
+				Type:        schema.NewNullableNamedType("intervals_all_of").Encode(),
+			},
+			"any_of": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns intervals produced by any of its sub-rules."),
+				Type:        schema.NewNullableNamedType("intervals_any_of").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-match
+	"intervals_match": {
+		Fields: schema.ObjectTypeFields{
+			"query": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Text you wish to find in the provided field."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"max_gaps": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Maximum number of positions between the matching terms. Terms further apart than this are not considered matches. Defaults to -1 (no restriction)."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"ordered": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) If true, matching terms must appear in their specified order. Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"analyzer": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Analyzer used to analyze the terms in the query. Defaults to the top-level field's analyzer."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-prefix
+	"intervals_prefix": {
+		Fields: schema.ObjectTypeFields{
+			"prefix": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Beginning characters of terms you wish to find in the top-level field."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"analyzer": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Analyzer used to analyze the terms in the query. Defaults to the top-level field's analyzer."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-wildcard
+	"intervals_wildcard": {
+		Fields: schema.ObjectTypeFields{
+			"pattern": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) Wildcard pattern used to find matching terms."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"analyzer": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Analyzer used to analyze the terms in the query. Defaults to the top-level field's analyzer."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-fuzzy
+	"intervals_fuzzy": {
+		Fields: schema.ObjectTypeFields{
+			"term": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The term to match."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"fuzziness": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Maximum edit distance allowed for matching, e.g. `AUTO`, `0`, `1` or `2`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"prefix_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Number of beginning characters left unchanged when creating expansions. Defaults to 0."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"transpositions": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Indicates whether edits include transpositions of two adjacent characters (ab → ba). Defaults to true."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"analyzer": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Analyzer used to analyze the terms in the query. Defaults to the top-level field's analyzer."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-all_of
+	"intervals_all_of": {
+		Fields: schema.ObjectTypeFields{
+			"intervals": schema.ObjectField{
+				Description: utils.ToPtr("(Required, array of rule objects) An array of rules to combine. All rules must produce a match in a document for the overall source to match."),
+				Type:        schema.NewArrayType(schema.NewNamedType("intervals_query")).Encode(),
+			},
+			"max_gaps": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Maximum number of positions between the matching terms. Terms further apart than this are not considered matches. Defaults to -1 (no restriction)."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"ordered": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) If true, matching terms must appear in their specified order. Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-intervals-query.html#intervals-any_of
+	"intervals_any_of": {
+		Fields: schema.ObjectTypeFields{
+			"intervals": schema.ObjectField{
+				Description: utils.ToPtr("(Required, array of rule objects) An array of rules to match."),
+				Type:        schema.NewArrayType(schema.NewNamedType("intervals_query")).Encode(),
+			},
+		},
+	},
+}
+
+// SuggestObjectTypes are the object types of the arguments and the result of the `suggest_<index>` functions.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html
+var SuggestObjectTypes = map[string]schema.ObjectType{
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html#completion-suggester
+	"completion_suggester": {
+		Fields: schema.ObjectTypeFields{
+			"field": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The completion field to suggest from."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The number of suggestions to return. Defaults to 5."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"skip_duplicates": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether duplicate suggestions should be filtered out. Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"fuzzy": schema.ObjectField{
+				Description: utils.ToPtr("(Optional) Returns suggestions with typos, based on the given fuzzy options."),
+				Type:        schema.NewNullableNamedType("completion_fuzzy_options").Encode(),
+			},
+			"contexts": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, object) The contexts to filter or boost the suggestions on, by context name, for completion fields with contexts."),
+				Type:        schema.NewNullableNamedType("json").Encode(),
+			},
+		},
+	},
+	"completion_fuzzy_options": {
+		Fields: schema.ObjectTypeFields{
+			"fuzziness": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Maximum edit distance allowed for matching, e.g. `AUTO`, `1` or `2`. Defaults to `AUTO`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"transpositions": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether transpositions are counted as one change instead of two. Defaults to true."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+			"min_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Minimum length of the input before fuzzy suggestions are returned. Defaults to 3."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"prefix_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) Minimum length of the input which is not checked for fuzzy alternatives. Defaults to 1."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"unicode_aware": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, Boolean) Whether the measurements are in Unicode code points instead of bytes. Defaults to false."),
+				Type:        schema.NewNullableNamedType("boolean").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html#term-suggester
+	"term_suggester": {
+		Fields: schema.ObjectTypeFields{
+			"field": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The field to suggest terms from."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The maximum number of suggestions per term. Defaults to 5."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"suggest_mode": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) Which terms to suggest for: `missing`, `popular` or `always`. Defaults to `missing`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"sort": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, string) How to sort the suggestions of a term: `score` or `frequency`. Defaults to `score`."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+			"max_edits": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The maximum edit distance of the suggestions, 1 or 2. Defaults to 2."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"prefix_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The number of leading characters which must match. Defaults to 1."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"min_word_length": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The minimum length of a suggestion. Defaults to 4."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+		},
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-suggesters.html#phrase-suggester
+	"phrase_suggester": {
+		Fields: schema.ObjectTypeFields{
+			"field": schema.ObjectField{
+				Description: utils.ToPtr("(Required, string) The text field to suggest phrases from."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The number of suggestions to return. Defaults to 5."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"gram_size": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, integer) The maximum size of the n-grams (shingles) of the field."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"confidence": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, float) The minimum score of a suggestion, relative to the score of the input phrase. Defaults to 1.0."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"max_errors": schema.ObjectField{
+				Description: utils.ToPtr("(Optional, float) The maximum number (or percentage if less than 1) of misspelled terms in a suggestion. Defaults to 1.0."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+		},
+	},
+	"suggest_result": {
+		Fields: schema.ObjectTypeFields{
+			"completion": schema.ObjectField{
+				Description: utils.ToPtr("The suggestions of the completion suggester."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("suggestion"))).Encode(),
+			},
+			"term": schema.ObjectField{
+				Description: utils.ToPtr("The suggestions of the term suggester, for each term of the text."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("suggestion"))).Encode(),
+			},
+			"phrase": schema.ObjectField{
+				Description: utils.ToPtr("The suggestions of the phrase suggester."),
+				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("suggestion"))).Encode(),
+			},
+		},
+	},
+	"suggestion": {
+		Fields: schema.ObjectTypeFields{
+			"text": schema.ObjectField{
+				Description: utils.ToPtr("The text the suggestions are for."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"offset": schema.ObjectField{
+				Description: utils.ToPtr("The offset of the text in the input text."),
+				Type:        schema.NewNamedType("integer").Encode(),
+			},
+			"length": schema.ObjectField{
+				Description: utils.ToPtr("The length of the text in the input text."),
+				Type:        schema.NewNamedType("integer").Encode(),
+			},
+			"options": schema.ObjectField{
+				Description: utils.ToPtr("The suggested texts."),
+				Type:        schema.NewArrayType(schema.NewNamedType("suggestion_option")).Encode(),
+			},
+		},
+	},
+	"suggestion_option": {
+		Fields: schema.ObjectTypeFields{
+			"text": schema.ObjectField{
+				Description: utils.ToPtr("The suggested text."),
+				Type:        schema.NewNamedType("keyword").Encode(),
+			},
+			"score": schema.ObjectField{
+				Description: utils.ToPtr("The score of the suggestion."),
+				Type:        schema.NewNullableNamedType("double").Encode(),
+			},
+			"freq": schema.ObjectField{
+				Description: utils.ToPtr("The number of documents containing the suggested term (term suggester only)."),
+				Type:        schema.NewNullableNamedType("integer").Encode(),
+			},
+			"_id": schema.ObjectField{
+				Description: utils.ToPtr("The id of the document of the suggestion (completion suggester only)."),
+				Type:        schema.NewNullableNamedType("keyword").Encode(),
+			},
+		},
+	},
+}
+
+// SuggesterFieldTypes are the field types each suggester can suggest from.
+var SuggesterFieldTypes = map[string]map[string]bool{
+	"completion": {"completion": true},
+	"term":       {"text": true, "match_only_text": true, "keyword": true},
+	"phrase":     {"text": true},
 }
 
 var UnsupportedRangeQueryScalars = []string{"binary", "completion", "_id", "wildcard", "match_only_text", "search_as_you_type"}
 
+// UnorderedScalars are the scalars that support the `range` query, but have no meaningful order for the `_gt`, `_gte`, `_lt` and `_lte` operators.
+var UnorderedScalars = []string{"boolean", "text"}
+
 var CollectionArgumentsMap = map[string]schema.ArgumentInfo{
 	// used for paginating more than 10,000 results
 	// https://www.elastic.co/guide/en/elasticsearch/reference/current/paginate-search-results.html#search-after
@@ -398,6 +1343,46 @@ var CollectionArgumentsMap = map[string]schema.ArgumentInfo{
 		Type:        schema.NewNullableNamedType("json").Encode(),
 		Description: utils.ToPtr(`(Optional) The 'search_after' operator in Elasticsearch, used for paginating more than 10,000 results.`),
 	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-search.html#search-api-min-score
+	"min_score": {
+		Type:        schema.NewNullableNamedType("double").Encode(),
+		Description: utils.ToPtr(`(Optional) Minimum '_score' for matching documents. Documents with a lower '_score' are not included in the results.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/sort-search-results.html#_track_scores
+	"track_scores": {
+		Type:        schema.NewNullableNamedType("boolean").Encode(),
+		Description: utils.ToPtr(`(Optional) If true, the '_score' is calculated for every hit even when the results are sorted on a field.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/highlighting.html
+	"highlight": {
+		Type:        schema.NewNullableNamedType("highlight_options").Encode(),
+		Description: utils.ToPtr(`(Optional) Highlights the matches of the query in the given fields. The highlighted fragments are returned in the '_highlight' column.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/collapse-search-results.html
+	"collapse": {
+		Type:        schema.NewNullableNamedType("collapse_options").Encode(),
+		Description: utils.ToPtr(`(Optional) Collapses the results on the values of a field, returning one result per value.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-composite-aggregation.html
+	"group_by": {
+		Type:        schema.NewNullableNamedType("group_by_options").Encode(),
+		Description: utils.ToPtr(`(Optional) Groups the documents by the given dimensions. The groups are returned as rows, with the values of the dimensions in their columns and the group in the '_group' column.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-mlt-query.html
+	"more_like_this": {
+		Type:        schema.NewNullableNamedType("more_like_this_options").Encode(),
+		Description: utils.ToPtr(`(Optional) Returns the documents similar to the given documents or text, ranked by similarity. The predicates of the query only filter the similar documents.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-bucket-filter-aggregation.html
+	"aggregate_predicates": {
+		Type:        schema.NewNullableNamedType("json").Encode(),
+		Description: utils.ToPtr(`(Optional) An object of predicates, by aggregate name, in the format of the predicates of NDC queries. An aggregate with a predicate is only computed on the documents which match the predicate.`),
+	},
+	// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-aggregations-metrics-cardinality-aggregation.html#_counts_are_approximate
+	"distinct_count": {
+		Type:        schema.NewNullableNamedType("distinct_count_options").Encode(),
+		Description: utils.ToPtr(`(Optional) The mode of the distinct counts of the aggregates: approximate, with a precision threshold, or exact. The distinct counts of the aggregates of group_by are always approximate.`),
+	},
 }
 
 // getComparisonOperatorDefinition generates and returns a map of comparison operators based on the provided data type.
@@ -405,7 +1390,7 @@ func getComparisonOperatorDefinition(dataType string) map[string]schema.Comparis
 	var comparisonOperators = map[string]schema.ComparisonOperatorDefinition{
 		"match":        schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode(),
 		"match_phrase": schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode(),
-		"term":         schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode(),
+		"term":         schema.NewComparisonOperatorEqual().Encode(),
 		"terms":        schema.NewComparisonOperatorCustom(schema.NewArrayType(schema.NewNamedType(dataType))).Encode(),
 	}
 
@@ -420,6 +1405,12 @@ func getComparisonOperatorDefinition(dataType string) map[string]schema.Comparis
 
 	if dataType == "text" {
 		comparisonOperators["match_phrase_prefix"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		comparisonOperators["match_with_options"] = schema.NewComparisonOperatorCustom(schema.NewNamedType("match_options")).Encode()
+		comparisonOperators["intervals"] = schema.NewComparisonOperatorCustom(schema.NewNamedType("intervals_query")).Encode()
+	}
+
+	if dataType == "search_as_you_type" {
+		comparisonOperators["search_as_you_type"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
 	}
 
 	if dataType == "text" || dataType == "keyword" || dataType == "wildcard" {
@@ -427,9 +1418,184 @@ func getComparisonOperatorDefinition(dataType string) map[string]schema.Comparis
 		comparisonOperators["regexp"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
 		comparisonOperators["prefix"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
 		comparisonOperators["match_bool_prefix"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		comparisonOperators["fuzzy"] = schema.NewComparisonOperatorCustom(schema.NewNamedType("fuzzy_query")).Encode()
+		comparisonOperators["terms_set"] = schema.NewComparisonOperatorCustom(schema.NewNamedType("terms_set_query")).Encode()
+	}
+
+	addStandardComparisonOperators(comparisonOperators, dataType)
+
+	return comparisonOperators
+}
+
+// StandardComparisonOperators maps the standard comparison operators to the Elasticsearch query they are executed as.
+// They are exposed alongside the Elasticsearch query operators, so that generic tools can use the connector like other NDC sources.
+var StandardComparisonOperators = map[string]string{
+	"_eq":      "term",
+	"_neq":     "term",
+	"_in":      "terms",
+	"_gt":      "range",
+	"_gte":     "range",
+	"_lt":      "range",
+	"_lte":     "range",
+	"_like":    "wildcard",
+	"_ilike":   "wildcard",
+	"_ieq":     "term",
+	"_iprefix": "prefix",
+}
+
+// CaseInsensitiveOperators are the standard comparison operators that are executed with the `case_insensitive` flag of their query.
+var CaseInsensitiveOperators = map[string]bool{
+	"_ieq":     true,
+	"_iprefix": true,
+	"_ilike":   true,
+}
+
+// addStandardComparisonOperators adds the standard comparison operators that are supported by the given operators of a data type.
+// `term` keeps the standard `equal` kind, so `_eq` is declared as a custom alias of it, and `_in` with the standard `in` kind.
+// The ordering operators are added if the type supports `range` and has an order (i.e. not `boolean` or `text`),
+// and the pattern and case-insensitive operators (`_like`, `_ilike`, `_ieq` and `_iprefix`) if the type supports `wildcard`.
+func addStandardComparisonOperators(comparisonOperators map[string]schema.ComparisonOperatorDefinition, dataType string) {
+	comparisonOperators["_eq"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+	comparisonOperators["_neq"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+	comparisonOperators["_in"] = schema.NewComparisonOperatorIn().Encode()
+
+	if _, ok := comparisonOperators["range"]; ok && !slices.Contains(UnorderedScalars, dataType) {
+		for _, operator := range []string{"_gt", "_gte", "_lt", "_lte"} {
+			comparisonOperators[operator] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		}
+	}
+
+	if _, ok := comparisonOperators["wildcard"]; ok {
+		comparisonOperators["_like"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		comparisonOperators["_ilike"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		comparisonOperators["_ieq"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+		comparisonOperators["_iprefix"] = schema.NewComparisonOperatorCustom(schema.NewNamedType(dataType)).Encode()
+	}
+}
+
+// RangeFieldTypes maps the range field types to the type of their bounds.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/range.html
+var RangeFieldTypes = map[string]string{
+	"integer_range": "integer",
+	"float_range":   "float",
+	"long_range":    "long",
+	"double_range":  "double",
+	"date_range":    "date",
+	"ip_range":      "ip",
+}
+
+// RangeRelationOperators maps the comparison operators of range field types
+// to the value of the `relation` parameter of the range query.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-range-query.html#querying-range-fields
+var RangeRelationOperators = map[string]string{
+	"contains":   "CONTAINS",
+	"within":     "WITHIN",
+	"intersects": "INTERSECTS",
+}
+
+// getRangeBoundsObjectType returns the `<type>_bounds` object type of the argument of the range relation operators of a range field type,
+// whose bounds have the given type.
+func getRangeBoundsObjectType(boundsType string) schema.ObjectType {
+	boundsFields := schema.ObjectTypeFields{
+		"gt": schema.ObjectField{
+			Description: utils.ToPtr("(Optional) Greater than."),
+			Type:        schema.NewNullableNamedType(boundsType).Encode(),
+		},
+		"lt": schema.ObjectField{
+			Description: utils.ToPtr("(Optional) Less than."),
+			Type:        schema.NewNullableNamedType(boundsType).Encode(),
+		},
+		"gte": schema.ObjectField{
+			Description: utils.ToPtr("(Optional) Greater than or equal."),
+			Type:        schema.NewNullableNamedType(boundsType).Encode(),
+		},
+		"lte": schema.ObjectField{
+			Description: utils.ToPtr("(Optional) Less than or equal."),
+			Type:        schema.NewNullableNamedType(boundsType).Encode(),
+		},
+	}
+	if boundsType == "date" {
+		boundsFields["format"] = schema.ObjectField{
+			Description: utils.ToPtr("(Optional, string) Date format used to convert date values in the query."),
+			Type:        schema.NewNullableNamedType("keyword").Encode(),
+		}
+		boundsFields["time_zone"] = schema.ObjectField{
+			Description: utils.ToPtr("(Optional, string) Coordinated Universal Time (UTC) offset or IANA time zone used to convert date values in the query to UTC."),
+			Type:        schema.NewNullableNamedType("keyword").Encode(),
+		}
+	}
+	return schema.ObjectType{
+		Fields: boundsFields,
+	}
+}
+
+// getRangeFieldComparisonOperatorDefinition generates and returns a map of comparison operators for a range field type.
+// The range relation operators take a `<type>_bounds` object as argument (see ObjectTypeMap).
+// The term operator takes a single value, and matches the documents whose range contains it.
+func getRangeFieldComparisonOperatorDefinition(dataType string) map[string]schema.ComparisonOperatorDefinition {
+	boundsType := RangeFieldTypes[dataType]
+	boundsObjectType := dataType + "_bounds"
+
+	comparisonOperators := map[string]schema.ComparisonOperatorDefinition{
+		"term": schema.NewComparisonOperatorCustom(schema.NewNamedType(boundsType)).Encode(),
+	}
+	for operator := range RangeRelationOperators {
+		comparisonOperators[operator] = schema.NewComparisonOperatorCustom(schema.NewNamedType(boundsObjectType)).Encode()
 	}
 
-	comparisonOperators["term"] = schema.NewComparisonOperatorEqual().Encode()
+	return comparisonOperators
+}
+
+// getIdComparisonOperatorDefinition generates and returns a map of comparison operators for the `_id` column.
+// Only the queries that Elasticsearch allows on `_id` are exposed; `term`, `terms`, `_eq` and `_in` are executed as an `ids` query.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/mapping-id-field.html
+func getIdComparisonOperatorDefinition() map[string]schema.ComparisonOperatorDefinition {
+	comparisonOperators := map[string]schema.ComparisonOperatorDefinition{
+		"term":   schema.NewComparisonOperatorEqual().Encode(),
+		"terms":  schema.NewComparisonOperatorCustom(schema.NewArrayType(schema.NewNamedType("_id"))).Encode(),
+		"prefix": schema.NewComparisonOperatorCustom(schema.NewNamedType("_id")).Encode(),
+	}
+	addStandardComparisonOperators(comparisonOperators, "_id")
+
+	return comparisonOperators
+}
+
+// OptionsQueries maps the comparison operators that take an object of options as argument
+// to the Elasticsearch query they are executed as.
+var OptionsQueries = map[string]string{
+	"fuzzy":              "fuzzy",
+	"terms_set":          "terms_set",
+	"intervals":          "intervals",
+	"match_with_options": "match",
+}
+
+// GeoPointAggregations are the aggregations supported by geo_point fields, which are always aggregated on the field itself.
+var GeoPointAggregations = map[string]bool{
+	"geo_bounds":   true,
+	"geo_centroid": true,
+}
+
+// IpQueries are the query operators supported by ip fields.
+// ip fields are always queried on the field itself, never on a text or keyword subfield.
+var IpQueries = map[string]bool{
+	"term":    true,
+	"terms":   true,
+	"range":   true,
+	"in_cidr": true,
+	"__sort":  true, // __sort is a custom operator that represents sorting operation (this is *NOT* an elasticsearch operator)
+}
+
+// getIpComparisonOperatorDefinition generates and returns a map of comparison operators for the ip type.
+// `in_cidr` takes an IPv4 or IPv6 CIDR block (e.g. `192.168.0.0/16`), and `range` takes an `ip_range_query` object.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/ip.html#query-ip-fields
+func getIpComparisonOperatorDefinition() map[string]schema.ComparisonOperatorDefinition {
+	comparisonOperators := map[string]schema.ComparisonOperatorDefinition{
+		"term":    schema.NewComparisonOperatorEqual().Encode(),
+		"terms":   schema.NewComparisonOperatorCustom(schema.NewArrayType(schema.NewNamedType("ip"))).Encode(),
+		"in_cidr": schema.NewComparisonOperatorCustom(schema.NewNamedType("keyword")).Encode(),
+		"range":   schema.NewComparisonOperatorCustom(schema.NewNamedType("ip_range_query")).Encode(),
+	}
+	addStandardComparisonOperators(comparisonOperators, "ip")
 
 	return comparisonOperators
 }
@@ -439,15 +1605,31 @@ func getAggregationFunctions(functions []string, typeName string) schema.ScalarT
 	aggregationFunctions := make(schema.ScalarTypeAggregateFunctions)
 
 	for _, function := range functions {
+		// the result of `top_metrics` is a value of the column, so it has the type of the column
+		resultTypeName := typeName
 		if function == "cardinality" || function == "value_count" {
-			typeName = "integer"
-		} else if function == "stats" || function == "string_stats" {
-			typeName = function
+			resultTypeName = "integer"
+		} else if function == "stats" || function == "string_stats" || function == "percentiles" || function == "extended_stats" {
+			resultTypeName = function
+		} else if function == "median_absolute_deviation" || function == "weighted_avg" {
+			resultTypeName = "double"
+		} else if function == "geo_bounds" {
+			resultTypeName = "geojson_polygon"
+		} else if function == "geo_centroid" {
+			resultTypeName = "geojson_point"
+		}
+
+		// the percentile ranks are returned as an array of the configured values and their rank
+		if function == "percentile_ranks" {
+			aggregationFunctions[function] = schema.AggregateFunctionDefinition{
+				ResultType: schema.NewArrayType(schema.NewNamedType("percentile_rank")).Encode(),
+			}
+			continue
 		}
 
 		// Generate the function definition and add it to the map
 		aggregationFunctions[function] = schema.AggregateFunctionDefinition{
-			ResultType: schema.NewNamedType(typeName).Encode(),
+			ResultType: schema.NewNamedType(resultTypeName).Encode(),
 		}
 	}
 
@@ -499,22 +1681,28 @@ var UnSupportedSortDataTypes = []string{
 	"join",
 	"range",
 	"_id",
+	"integer_range",
+	"float_range",
+	"long_range",
+	"double_range",
+	"date_range",
+	"ip_range",
 }
 
 // TermLevelQueries queries in elasticsearch for keyword family of types
 // more reading: https://www.elastic.co/guide/en/elasticsearch/reference/current/term-level-queries.html
 var TermLevelQueries = map[string]bool{
-	"exists":   true,
-	"fuzzy":    true,
-	"ids":      true,
-	"prefix":   true,
-	"range":    true,
-	"regexp":   true,
-	"term":     true,
-	"terms":    true,
-	"term_set": true,
-	"wildcard": true,
-	"__sort":   true, // __sort is a custom operator that represents sorting operation (this is *NOT* an elasticsearch operator)
+	"exists":    true,
+	"fuzzy":     true,
+	"ids":       true,
+	"prefix":    true,
+	"range":     true,
+	"regexp":    true,
+	"term":      true,
+	"terms":     true,
+	"terms_set": true,
+	"wildcard":  true,
+	"__sort":    true, // __sort is a custom operator that represents sorting operation (this is *NOT* an elasticsearch operator)
 }
 
 var TermLevelAggregations = map[string]bool{
@@ -530,13 +1718,21 @@ var NumericalQueries = map[string]bool{
 }
 
 var NumericalAggregations = map[string]bool{
-	"max":         true,
-	"min":         true,
-	"sum":         true,
-	"avg":         true,
-	"value_count": true,
-	"cardinality": true,
-	"stats":       true,
+	"max":                       true,
+	"min":                       true,
+	"sum":                       true,
+	"avg":                       true,
+	"value_count":               true,
+	"cardinality":               true,
+	"stats":                     true,
+	"percentiles":               true,
+	"percentile_ranks":          true,
+	"median_absolute_deviation": true,
+	"histogram":                 true,
+	"range":                     true,
+	"extended_stats":            true,
+	"top_metrics":               true,
+	"weighted_avg":              true,
 }
 
 // FullTextQueries queries in elasticsearch for text family of types
@@ -560,7 +1756,35 @@ var FullTextAggregations = map[string]bool{}
 var TextFamilyOfTypes = map[string]bool{
 	"text":            true,
 	"match_only_text": true,
-	"ip":              true,
+}
+
+// HighlightFieldTypes are the field types exposed in the `_highlight` column of a collection.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/highlighting.html
+var HighlightFieldTypes = map[string]bool{
+	"text":               true,
+	"match_only_text":    true,
+	"search_as_you_type": true,
+	"keyword":            true,
+	"constant_keyword":   true,
+	"wildcard":           true,
+}
+
+// NoDocValuesTypes are the field types without doc values, which can only be retrieved from `_source`.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/doc-values.html
+var NoDocValuesTypes = map[string]bool{
+	"text":               true,
+	"match_only_text":    true,
+	"annotated_text":     true,
+	"search_as_you_type": true,
+	"completion":         true,
+	"binary":             true,
+}
+
+// FieldsAPITypes are the field types whose values may not be part of `_source`, and are always retrieved with the `fields` option.
+// e.g. the value of a `constant_keyword` field is only stored in the mapping.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-fields.html#search-fields-param
+var FieldsAPITypes = map[string]bool{
+	"constant_keyword": true,
 }
 
 // Used for structured content like email addresses, hostnames, status codes, zip codes or tags.
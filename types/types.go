@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/hasura/ndc-elasticsearch/elasticsearch"
@@ -25,8 +27,11 @@ type State struct {
 
 // Configuration contains required settings for the connector.
 type Configuration struct {
-	Indices map[string]interface{} `json:"indices"`
-	Queries map[string]NativeQuery `json:"queries"`
+	Indices       map[string]interface{}             `json:"indices"`
+	Queries       map[string]NativeQuery             `json:"queries"`
+	Scripts       map[string]Script                  `json:"scripts,omitempty"`
+	RuntimeFields map[string]map[string]RuntimeField `json:"runtime_fields,omitempty"`
+	Aggregations  *AggregationSettings               `json:"aggregations,omitempty"`
 }
 
 func (c *Configuration) GetIndex(indexName string) (map[string]interface{}, error) {
@@ -50,6 +55,10 @@ func (c *Configuration) GetFieldMap(indexName, fieldPath string) (map[string]int
 		return nil, fmt.Errorf("unable to find mapping in index: %s", indexName)
 	}
 
+	if runtimeField, ok := c.RuntimeFields[indexName][fieldPath]; ok {
+		return runtimeField.GetFieldMap(), nil
+	}
+
 	splitFieldPath := strings.Split(fieldPath, ".")
 	curFieldPath := ""
 
@@ -99,6 +108,260 @@ func (c *Configuration) IsFieldNested(indexName, fieldPath string) (bool, error)
 	return fieldMap["type"] == "nested", nil
 }
 
+const (
+	// SourceRetrieval retrieves the values of a field from `_source`.
+	SourceRetrieval = "_source"
+	// FieldsRetrieval retrieves the values of a field with the `fields` option.
+	FieldsRetrieval = "fields"
+	// DocValueFieldsRetrieval retrieves the values of a field from its doc values, with the `docvalue_fields` option.
+	DocValueFieldsRetrieval = "docvalue_fields"
+)
+
+// GetFieldRetrieval returns how the values of the given field are retrieved from the documents of a search,
+// based on the mappings of the index:
+//   - runtime and `constant_keyword` fields are retrieved with the `fields` option.
+//   - fields which are not part of `_source` (`_source` disabled, or the field excluded from it) are retrieved from their doc values.
+//   - other fields, and fields of nested documents, are retrieved from `_source`.
+//
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/search-fields.html
+func (c *Configuration) GetFieldRetrieval(indexName, fieldPath string) string {
+	if c.IsRuntimeField(indexName, fieldPath) {
+		return FieldsRetrieval
+	}
+
+	// the fields of nested documents are returned as arrays of objects by the fields API, so they are only read from `_source`
+	splitFieldPath := strings.Split(fieldPath, ".")
+	for i := 1; i < len(splitFieldPath); i++ {
+		if isNested, _ := c.IsFieldNested(indexName, strings.Join(splitFieldPath[:i], ".")); isNested {
+			return SourceRetrieval
+		}
+	}
+
+	fieldMap, err := c.GetFieldMap(indexName, fieldPath)
+	if err != nil {
+		return SourceRetrieval
+	}
+	fieldType, isScalar := internal.FieldTypeIsScalar(fieldMap)
+	if !isScalar {
+		return SourceRetrieval
+	}
+
+	if internal.FieldsAPITypes[fieldType] {
+		return FieldsRetrieval
+	}
+
+	if !c.isFieldInSource(indexName, fieldPath) {
+		docValues, ok := fieldMap["doc_values"].(bool)
+		if (!ok || docValues) && !internal.NoDocValuesTypes[fieldType] {
+			return DocValueFieldsRetrieval
+		}
+	}
+
+	return SourceRetrieval
+}
+
+// isFieldInSource checks if the given field is part of the `_source` of the documents of the index,
+// based on the `_source` settings of the index mappings.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/mapping-source-field.html
+func (c *Configuration) isFieldInSource(indexName, fieldPath string) bool {
+	index, err := c.GetIndex(indexName)
+	if err != nil {
+		return true
+	}
+	mapping, _ := index["mappings"].(map[string]interface{})
+	source, ok := mapping["_source"].(map[string]interface{})
+	if !ok {
+		return true
+	}
+
+	if enabled, ok := source["enabled"].(bool); ok && !enabled {
+		return false
+	}
+
+	if includes, ok := source["includes"].([]interface{}); ok && len(includes) != 0 && !matchesSourcePatterns(fieldPath, includes) {
+		return false
+	}
+
+	if excludes, ok := source["excludes"].([]interface{}); ok && matchesSourcePatterns(fieldPath, excludes) {
+		return false
+	}
+
+	return true
+}
+
+// matchesSourcePatterns checks if the given field, or one of its parent objects, matches one of the `_source` patterns.
+func matchesSourcePatterns(fieldPath string, patterns []interface{}) bool {
+	splitFieldPath := strings.Split(fieldPath, ".")
+	for i := 1; i <= len(splitFieldPath); i++ {
+		path := strings.Join(splitFieldPath[:i], ".")
+		for _, pattern := range patterns {
+			pattern, ok := pattern.(string)
+			if !ok {
+				continue
+			}
+			if matched, _ := filepath.Match(pattern, path); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Script contains the definition of a named script, which is stored in Elasticsearch by `cli update`
+// and exposed as an argument of the collection of its index.
+type Script struct {
+	Index       string            `json:"index"`
+	Source      string            `json:"source"`
+	Lang        string            `json:"lang,omitempty"`
+	Params      map[string]string `json:"params,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// GetLang returns the language of the script, which defaults to painless.
+func (s Script) GetLang() string {
+	if s.Lang == "" {
+		return "painless"
+	}
+	return s.Lang
+}
+
+// GetStoredScriptId returns the id of the stored script of the script with the given name.
+// The id is namespaced by the connector and the index of the script, so that the scripts of different connectors
+// or indices sharing an Elasticsearch cluster never overwrite each other.
+func (s Script) GetStoredScriptId(name string) string {
+	return "ndc_elasticsearch." + s.Index + "." + name
+}
+
+// RuntimeField contains the definition of a runtime field, which is computed by a script at query time
+// and exposed as a column of the collection of its index.
+type RuntimeField struct {
+	Type   string `json:"type"`
+	Script string `json:"script"`
+}
+
+// GetFieldMap returns the mapping of the runtime field, as it would appear in the properties of the index.
+func (r RuntimeField) GetFieldMap() map[string]interface{} {
+	return map[string]interface{}{
+		"type": r.Type,
+	}
+}
+
+// GetRuntimeMappings returns the runtime mappings of the given index, to be sent with the search request.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/runtime-search-request.html
+func (c *Configuration) GetRuntimeMappings(indexName string) map[string]interface{} {
+	runtimeFields := c.RuntimeFields[indexName]
+	if len(runtimeFields) == 0 {
+		return nil
+	}
+
+	runtimeMappings := make(map[string]interface{}, len(runtimeFields))
+	for fieldName, runtimeField := range runtimeFields {
+		runtimeMappings[fieldName] = map[string]interface{}{
+			"type": runtimeField.Type,
+			"script": map[string]interface{}{
+				"source": runtimeField.Script,
+			},
+		}
+	}
+	return runtimeMappings
+}
+
+// IsRuntimeField checks if the given field path is a runtime field of the given index.
+func (c *Configuration) IsRuntimeField(indexName, fieldPath string) bool {
+	_, ok := c.RuntimeFields[indexName][fieldPath]
+	return ok
+}
+
+// DefaultPercents are the percentiles computed by the `percentiles` aggregate function when no percents are configured.
+var DefaultPercents = []float64{50, 90, 95, 99}
+
+// AggregationSettings contains the parameters of the aggregate functions which take parameters in Elasticsearch,
+// since the aggregate functions of NDC have no arguments.
+type AggregationSettings struct {
+	// Percents are the percentiles computed by the `percentiles` aggregate function.
+	Percents []float64 `json:"percents,omitempty"`
+	// PercentileRankValues are the values whose percentile rank is computed by the `percentile_ranks` aggregate function.
+	PercentileRankValues []float64 `json:"percentile_rank_values,omitempty"`
+	// TopMetricsSort are the sorts of the documents whose value is returned by the `top_metrics` aggregate function, by index.
+	TopMetricsSort map[string]TopMetricsSort `json:"top_metrics_sort,omitempty"`
+	// WeightFields are the fields which weight the values averaged by the `weighted_avg` aggregate function, by index.
+	WeightFields map[string]string `json:"weight_fields,omitempty"`
+	// DistinctCounts are the settings of the distinct counts (`count` with `distinct`) of the columns of each index, by index.
+	DistinctCounts map[string]DistinctCountSettings `json:"distinct_counts,omitempty"`
+}
+
+// DistinctCountSettings are the settings of the distinct counts of the columns of an index.
+// Distinct values are counted with the `cardinality` aggregation, which is approximate above its precision threshold,
+// unless they are counted exactly by paginating a `composite` aggregation.
+type DistinctCountSettings struct {
+	// PrecisionThreshold is the count below which the counts of the `cardinality` aggregation are expected to be close to accurate (at most 40000).
+	PrecisionThreshold int `json:"precision_threshold,omitempty"`
+	// ExactFields are the fields whose distinct values are counted exactly.
+	ExactFields []string `json:"exact_fields,omitempty"`
+}
+
+// IsExact returns whether the distinct values of a field are counted exactly.
+func (d DistinctCountSettings) IsExact(field string) bool {
+	return slices.Contains(d.ExactFields, field)
+}
+
+// TopMetricsSort is the sort of the documents of an index for the `top_metrics` aggregate function,
+// which returns the value of the first document.
+type TopMetricsSort struct {
+	Field string `json:"field"`
+	Order string `json:"order,omitempty"`
+}
+
+// GetOrder returns the order of the sort, which defaults to desc, so that the value at the highest sort key is returned.
+func (t TopMetricsSort) GetOrder() string {
+	if t.Order == "" {
+		return "desc"
+	}
+	return t.Order
+}
+
+// GetPercents returns the percentiles computed by the `percentiles` aggregate function, which default to DefaultPercents.
+func (a *AggregationSettings) GetPercents() []float64 {
+	if a == nil || len(a.Percents) == 0 {
+		return DefaultPercents
+	}
+	return a.Percents
+}
+
+// GetPercentileRankValues returns the values whose percentile rank is computed by the `percentile_ranks` aggregate function.
+func (a *AggregationSettings) GetPercentileRankValues() []float64 {
+	if a == nil {
+		return nil
+	}
+	return a.PercentileRankValues
+}
+
+// GetTopMetricsSort returns the sort of the `top_metrics` aggregate function for the given index, if any.
+func (a *AggregationSettings) GetTopMetricsSort(indexName string) (TopMetricsSort, bool) {
+	if a == nil {
+		return TopMetricsSort{}, false
+	}
+	sort, ok := a.TopMetricsSort[indexName]
+	return sort, ok
+}
+
+// GetWeightField returns the weight field of the `weighted_avg` aggregate function for the given index, if any.
+func (a *AggregationSettings) GetWeightField(indexName string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+	field, ok := a.WeightFields[indexName]
+	return field, ok
+}
+
+// GetDistinctCountSettings returns the settings of the distinct counts of the columns of the given index.
+func (a *AggregationSettings) GetDistinctCountSettings(indexName string) DistinctCountSettings {
+	if a == nil {
+		return DistinctCountSettings{}
+	}
+	return a.DistinctCounts[indexName]
+}
+
 // NativeQuery contains the definition of the native query.
 type NativeQuery struct {
 	DSL        DSL                     `json:"dsl"`
@@ -117,15 +380,61 @@ type DSL struct {
 type ReturnType struct {
 	Kind     string                  `json:"kind"`
 	Mappings *map[string]interface{} `json:"mappings,omitempty"`
+	// Aggregations are the result schemas of the aggregations of a native query of kind `aggregations`, by name.
+	Aggregations map[string]NativeAggregation `json:"aggregations,omitempty"`
+}
+
+// NativeAggregation is the result schema of an aggregation of a native query.
+type NativeAggregation struct {
+	// Type is the type of the Elasticsearch aggregation, e.g. `terms`, `date_histogram`, `filter` or `avg`.
+	Type string `json:"type"`
+	// KeyType is the scalar type of the keys of the buckets, for the bucket aggregations. Defaults to the type of the keys of the aggregation.
+	KeyType string `json:"key_type,omitempty"`
+	// Aggregations are the result schemas of the sub-aggregations of a bucket aggregation, by name.
+	Aggregations map[string]NativeAggregation `json:"aggregations,omitempty"`
 }
 
 // PostProcessor is used to post process the query response.
 type PostProcessor struct {
-	IsFields        bool
-	StarAggregates  string
-	ColumnAggregate map[string]bool
-	IsIDSelected    bool
-	SelectedFields  map[string]Field
+	IsFields            bool
+	StarAggregates      string
+	ColumnAggregate     map[string]bool
+	IsIDSelected        bool
+	IsScoreSelected     bool
+	IsHighlightSelected bool
+	IsGroupSelected     bool
+	SelectedFields      map[string]Field
+	FetchedFields       []string
+	// Collapsed is the post processor of the members of the collapsed groups, selected in the `_collapsed` column.
+	Collapsed       *PostProcessor
+	CollapsedFields schema.QueryFields
+	// AggregateFunctions are the functions of the column aggregates whose result is converted (e.g. `percentiles` or `top_metrics`), by name.
+	AggregateFunctions map[string]string
+	// GroupBy is the post processor of the groups returned as rows, when the query has the `group_by` argument.
+	GroupBy *GroupBy
+	// ExactDistinctCounts are the aggregates which count the distinct values of a column exactly, with a `composite` aggregation
+	// whose buckets are counted page by page after the search.
+	ExactDistinctCounts map[string]bool
+	// NativeAggregations are the aggregations of a native query of kind `aggregations`, returned as the columns of a single row.
+	NativeAggregations map[string]NativeAggregation
+	// NativeAggregationFields are the fields selected in the row of the aggregations of a native query of kind `aggregations`.
+	NativeAggregationFields schema.QueryFields
+}
+
+// GroupBy is used to post process the groups of a query with the `group_by` argument.
+type GroupBy struct {
+	// Mode is the aggregation the groups are built with: `composite` or `terms`.
+	Mode string
+	// Dimensions are the columns the documents are grouped by, in order.
+	Dimensions []string
+	// Aggregates is the post processor of the aggregates computed for each group.
+	Aggregates *PostProcessor
+	// RangeDimensions are the dimensions grouped by ranges, whose bounds are returned in the ranges of the groups.
+	RangeDimensions map[string]bool
+	// GridDimensions are the grids of the dimensions grouped by geo grid cells, which are returned in the cells of the groups.
+	GridDimensions map[string]string
+	// DateDimensions are the dimensions of date fields, whose keys are returned formatted, instead of as epoch milliseconds.
+	DateDimensions map[string]bool
 }
 
 // Field is used to represent a field in the query response.
@@ -135,3 +444,21 @@ type Field struct {
 }
 
 type Variable string
+
+// RangeRelationVariable is a variable holding the bounds of a range query on a range field.
+// The relation is added to the bounds once the variable is replaced.
+type RangeRelationVariable struct {
+	Name     Variable
+	Relation string
+}
+
+// LikePatternVariable is a variable holding the pattern of a `_like` or `_ilike` comparison.
+// The pattern is converted to a wildcard pattern once the variable is replaced.
+type LikePatternVariable Variable
+
+// MoreLikeThisVariable is a variable holding the options of the `more_like_this` collection argument.
+// The more_like_this query on the documents of the index is built once the variable is replaced.
+type MoreLikeThisVariable struct {
+	Name  Variable
+	Index string
+}
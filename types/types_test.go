@@ -308,6 +308,77 @@ func TestIsFieldNested(t *testing.T) {
 	}
 }
 
+func TestGetFieldRetrieval(t *testing.T) {
+	tests := []struct {
+		name      string
+		indexName string
+		fieldPath string
+		want      string
+	}{
+		{
+			name:      "source_field",
+			indexName: "audit",
+			fieldPath: "user",
+			want:      SourceRetrieval,
+		},
+		{
+			name:      "runtime_field",
+			indexName: "audit",
+			fieldPath: "user_upper",
+			want:      FieldsRetrieval,
+		},
+		{
+			name:      "constant_keyword_field",
+			indexName: "audit",
+			fieldPath: "env",
+			want:      FieldsRetrieval,
+		},
+		{
+			name:      "excluded_field",
+			indexName: "audit",
+			fieldPath: "payload_size",
+			want:      DocValueFieldsRetrieval,
+		},
+		{
+			name:      "excluded_object_field",
+			indexName: "audit",
+			fieldPath: "internal.trace_id",
+			want:      DocValueFieldsRetrieval,
+		},
+		{
+			name:      "excluded_field_without_doc_values",
+			indexName: "audit",
+			fieldPath: "internal.note",
+			want:      SourceRetrieval,
+		},
+		{
+			name:      "excluded_nested_field",
+			indexName: "audit",
+			fieldPath: "steps.duration",
+			want:      SourceRetrieval,
+		},
+		{
+			name:      "source_disabled",
+			indexName: "metrics",
+			fieldPath: "value",
+			want:      DocValueFieldsRetrieval,
+		},
+		{
+			name:      "source_disabled_text_field",
+			indexName: "metrics",
+			fieldPath: "label",
+			want:      SourceRetrieval,
+		},
+	}
+
+	config := getConfiguration(fieldRetrievalConfiguration)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, config.GetFieldRetrieval(tt.indexName, tt.fieldPath))
+		})
+	}
+}
+
 const configurationTransactions = `{
   "indices": {
     "customers": {
@@ -492,3 +563,68 @@ const multiLevelNestedConfiguration = `{
   },
   "queries": {}
 }`
+
+const fieldRetrievalConfiguration = `{
+  "indices": {
+    "audit": {
+      "mappings": {
+        "_source": {
+          "excludes": ["payload_size", "internal", "steps.duration"]
+        },
+        "properties": {
+          "env": {
+            "type": "constant_keyword",
+            "value": "production"
+          },
+          "internal": {
+            "properties": {
+              "note": {
+                "type": "text"
+              },
+              "trace_id": {
+                "type": "keyword"
+              }
+            }
+          },
+          "payload_size": {
+            "type": "long"
+          },
+          "steps": {
+            "type": "nested",
+            "properties": {
+              "duration": {
+                "type": "long"
+              }
+            }
+          },
+          "user": {
+            "type": "keyword"
+          }
+        }
+      }
+    },
+    "metrics": {
+      "mappings": {
+        "_source": {
+          "enabled": false
+        },
+        "properties": {
+          "label": {
+            "type": "text"
+          },
+          "value": {
+            "type": "double"
+          }
+        }
+      }
+    }
+  },
+  "runtime_fields": {
+    "audit": {
+      "user_upper": {
+        "type": "keyword",
+        "script": "emit(doc['user'].value.toUpperCase())"
+      }
+    }
+  }
+}`